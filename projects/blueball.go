@@ -1,15 +1,23 @@
+//go:build ignore
+// +build ignore
+
 package main
 
 import (
 	"log"
+	"math"
 	rt "raytracer/raytracer"
 )
 
 func main() {
-	cam := rt.Camera{200, 200}
+	view := rt.MakeViewTransform(rt.Point{0, 0, -4}, rt.Point{0, 0, 0}, rt.Vector{0, 1, 0})
+	cam := rt.NewCamera(200, 200, math.Pi/3, view)
+	w := rt.NewEmptyWorld()
+	w.AddLight(rt.NewPointLight(rt.Point{-10, 10, -10}, rt.White()))
 	s := rt.NewSphere(rt.MakeIdentity())
 	s.Material().Color = rt.Color{1, 0.2, 1}
-	canvas := cam.Render(s, rt.NewLightShader())
+	w.AddShape(s)
+	canvas := cam.Render(w)
 
 	err := canvas.WritePng("blueball.png")
 	if err != nil {
@@ -0,0 +1,59 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"log"
+	"math"
+	rt "raytracer/raytracer"
+)
+
+// Returns a matte wall of the given color.
+func wall(xf *rt.Matrix, color rt.Color) rt.Shape {
+	s := rt.NewPlane(xf)
+	s.Material().Color = color
+	s.Material().Specular = 0
+	return s
+}
+
+func main() {
+	w := rt.NewEmptyWorld()
+	white := rt.Color{0.8, 0.8, 0.8}
+	w.AddShape(wall(rt.MakeIdentity(), white))
+	w.AddShape(wall(rt.MakeTranslation(0, 2, 0), white))
+	w.AddShape(wall(rt.MakeRotationX(math.Pi/2).Translate(0, 0, 1), white))
+	w.AddShape(wall(rt.MakeRotationZ(math.Pi/2).Translate(-1, 0, 0), rt.Color{0.8, 0.1, 0.1}))
+	w.AddShape(wall(rt.MakeRotationZ(math.Pi/2).Translate(1, 0, 0), rt.Color{0.1, 0.8, 0.1}))
+
+	// The only light comes from a glowing panel in the ceiling.
+	panel := rt.NewCube(rt.MakeScaling(0.5, 0.01, 0.5).Translate(0, 2, 0))
+	panel.Material().Diffuse = 0
+	panel.Material().Specular = 0
+	panel.Material().Emissive = rt.Color{6, 6, 6}
+	w.AddShape(panel)
+
+	box := rt.NewCube(rt.MakeScaling(0.3, 0.6, 0.3).RotateY(0.4).Translate(-0.4, 0.6, 0.3))
+	box.Material().Color = white
+	box.Material().Specular = 0
+	w.AddShape(box)
+
+	ball := rt.NewSphere(rt.MakeScaling(0.35, 0.35, 0.35).Translate(0.45, 0.35, -0.3))
+	ball.Material().Color = white
+	ball.Material().Diffuse = 0.1
+	ball.Material().Reflective = 0.9
+	w.AddShape(ball)
+
+	view := rt.MakeViewTransform(rt.Point{0, 1, -3.4}, rt.Point{0, 1, 0}, rt.Vector{0, 1, 0})
+	cam := rt.NewCamera(200, 200, math.Pi/4, view)
+	opts := rt.DefaultRenderOptions()
+	opts.Shader = rt.NewPathTracer(32, 1)
+	opts.Samples = 2
+	opts.Sampling = rt.Jittered
+	canvas, _ := cam.RenderWithOptions(w, opts)
+
+	err := canvas.WritePng("cornellbox.png")
+	if err != nil {
+		log.Print(err)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"log"
+	"math"
+	rt "raytracer/raytracer"
+)
+
+// Tosses a ball along a projectile's path, as in rocket.go, and renders
+// it with the shutter open for part of its flight.
+func main() {
+	w := rt.NewEmptyWorld()
+	w.AddLight(rt.NewPointLight(rt.Point{-10, 10, -10}, rt.White()))
+
+	floor := rt.NewPlane(rt.MakeIdentity())
+	floor.Material().Color = rt.Color{1, 0.9, 0.9}
+	floor.Material().Specular = 0
+	w.AddShape(floor)
+
+	wall := rt.NewPlane(rt.MakeRotationX(math.Pi/2).Translate(0, 0, 5))
+	wall.Material().Color = rt.Color{0.9, 0.9, 1}
+	wall.Material().Specular = 0
+	w.AddShape(wall)
+
+	post := rt.NewCylinder(rt.MakeScaling(0.2, 1, 0.2).Translate(0, 0, 1), 0, 1.5, true)
+	post.Material().Color = rt.Color{0.4, 0.4, 0.9}
+	w.AddShape(post)
+
+	// Record the ball's position at each tick of the shutter.
+	ball := rt.NewSphere(rt.MakeIdentity())
+	ball.Material().Color = rt.Color{1, 0.3, 0.1}
+	loc := rt.Point{-2.5, 0.5, 0}
+	vel := rt.Vector{5, 4, 0}
+	gravity := rt.Vector{0, -9.8, 0}
+	const ticks = 10
+	keyframes := []rt.Keyframe{}
+	for i := 0; i <= ticks; i++ {
+		xf := rt.MakeScaling(0.3, 0.3, 0.3).Translate(loc.X, loc.Y, loc.Z)
+		keyframes = append(keyframes, rt.Keyframe{Time: float64(i) / ticks, Xform: xf})
+		loc = loc.PlusV(vel.Scale(1.0 / ticks))
+		vel = vel.Plus(gravity.Scale(1.0 / ticks))
+	}
+	ball.Animate(keyframes...)
+	w.AddShape(ball)
+
+	view := rt.MakeViewTransform(rt.Point{0, 1.5, -6}, rt.Point{0, 1, 0}, rt.Vector{0, 1, 0})
+	cam := rt.NewCamera(300, 150, math.Pi/3, view)
+	cam.ShutterOpen, cam.ShutterClose = 0.3, 0.45
+	opts := rt.DefaultRenderOptions()
+	opts.Samples = 8
+	opts.Sampling = rt.Jittered
+	canvas, _ := cam.RenderWithOptions(w, opts)
+
+	err := canvas.WritePng("motionblur.png")
+	if err != nil {
+		log.Print(err)
+	}
+}
@@ -1,3 +1,6 @@
+//go:build ignore
+// +build ignore
+
 package main
 
 import (
@@ -22,7 +25,7 @@ func main() {
 	e := environment{rt.Vector{0, -0.1, 0}, rt.Vector{-0.01, 0, 0}}
 	for p.loc.Y > 0 {
 		c.Set(int(p.loc.X), c.Height-int(p.loc.Y), rt.White())
-		p.loc = p.loc.PlusVector(p.vel)
+		p.loc = p.loc.PlusV(p.vel)
 		p.vel = p.vel.Plus(e.gravity).Plus(e.wind)
 	}
 
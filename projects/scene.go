@@ -0,0 +1,56 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"log"
+	"math"
+	rt "raytracer/raytracer"
+)
+
+func main() {
+	w := rt.NewEmptyWorld()
+	w.AddLight(rt.NewPointLight(rt.Point{-10, 10, -10}, rt.White()))
+
+	floor := rt.NewPlane(rt.MakeIdentity())
+	floor.Material().Color = rt.Color{1, 0.9, 0.9}
+	floor.Material().Specular = 0
+	floor.Material().Reflective = 0.2
+	w.AddShape(floor)
+
+	wall := rt.NewPlane(rt.MakeRotationX(math.Pi/2).Translate(0, 0, 5))
+	wall.Material().Color = rt.Color{0.9, 0.9, 1}
+	wall.Material().Specular = 0
+	w.AddShape(wall)
+
+	middle := rt.NewSphere(rt.MakeTranslation(-0.5, 1, 0.5))
+	middle.Material().Color = rt.Color{0.1, 1, 0.5}
+	middle.Material().Diffuse = 0.7
+	middle.Material().Specular = 0.3
+	w.AddShape(middle)
+
+	right := rt.NewSphere(rt.MakeScaling(0.5, 0.5, 0.5).Translate(1.5, 0.5, -0.5))
+	right.Material().Color = rt.Color{0.5, 1, 0.1}
+	right.Material().Diffuse = 0.7
+	right.Material().Specular = 0.3
+	w.AddShape(right)
+
+	left := rt.NewSphere(rt.MakeScaling(0.33, 0.33, 0.33).Translate(-1.5, 0.33, -0.75))
+	left.Material().Color = rt.Color{1, 0.8, 0.1}
+	left.Material().Diffuse = 0.7
+	left.Material().Specular = 0.3
+	w.AddShape(left)
+
+	view := rt.MakeViewTransform(rt.Point{0, 1.5, -5}, rt.Point{0, 1, 0}, rt.Vector{0, 1, 0})
+	cam := rt.NewCamera(300, 150, math.Pi/3, view)
+	opts := rt.DefaultRenderOptions()
+	opts.Samples = 3
+	opts.Filter = rt.MitchellFilter{R: 2, B: 1.0 / 3, C: 1.0 / 3}
+	canvas, _ := cam.RenderWithOptions(w, opts)
+
+	err := canvas.WritePng("scene.png")
+	if err != nil {
+		log.Print(err)
+	}
+}
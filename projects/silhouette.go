@@ -1,3 +1,6 @@
+//go:build ignore
+// +build ignore
+
 package main
 
 import (
@@ -7,9 +10,11 @@ import (
 )
 
 func main() {
-	cam := rt.Camera{200, 200}
-	s := rt.NewSphere(rt.MakeScaling(0.5, 1, 1).RotateZ(math.Pi / 4.0))
-	canvas := cam.Render(s, rt.PrimitiveShader{})
+	view := rt.MakeViewTransform(rt.Point{0, 0, -4}, rt.Point{0, 0, 0}, rt.Vector{0, 1, 0})
+	cam := rt.NewCamera(200, 200, math.Pi/3, view)
+	w := rt.NewEmptyWorld()
+	w.AddShape(rt.NewSphere(rt.MakeScaling(0.5, 1, 1).RotateZ(math.Pi / 4.0)))
+	canvas, _ := cam.RenderWithOptions(w, rt.RenderOptions{Shader: rt.PrimitiveShader{}})
 
 	err := canvas.WritePng("silhouette.png")
 	if err != nil {
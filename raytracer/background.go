@@ -0,0 +1,171 @@
+package raytracer
+
+import (
+	"math"
+	"sort"
+)
+
+// A Background gives the light arriving from infinitely far away, seen by
+// rays that miss everything in the world.
+type Background interface {
+	// Returns the light arriving along unit vector -dir, as seen looking
+	// along dir.
+	Radiance(dir Vector) Color
+	// Returns a direction chosen using (u, v) from the unit square, more
+	// often where the background is brighter, and its probability
+	// density per unit solid angle.
+	Sample(u, v float64) (Vector, float64)
+	// Returns the probability density with which Sample chooses dir.
+	PDF(dir Vector) float64
+}
+
+// SolidBackground is the same color in every direction.
+type SolidBackground struct {
+	Color Color
+}
+
+func (b SolidBackground) Radiance(dir Vector) Color {
+	return b.Color
+}
+
+func (b SolidBackground) Sample(u, v float64) (Vector, float64) {
+	return sampleSphere(u, v), 1 / (4 * math.Pi)
+}
+
+func (b SolidBackground) PDF(dir Vector) float64 {
+	return 1 / (4 * math.Pi)
+}
+
+// GradientBackground blends from Bottom straight down to Top straight up.
+type GradientBackground struct {
+	Bottom, Top Color
+}
+
+func (b GradientBackground) Radiance(dir Vector) Color {
+	f := (dir.Y + 1) / 2
+	return b.Bottom.scale(1 - f).plus(b.Top.scale(f))
+}
+
+func (b GradientBackground) Sample(u, v float64) (Vector, float64) {
+	return sampleSphere(u, v), 1 / (4 * math.Pi)
+}
+
+func (b GradientBackground) PDF(dir Vector) float64 {
+	return 1 / (4 * math.Pi)
+}
+
+// EnvironmentMap surrounds the world with a latitude-longitude image,
+// such as one read by ParseHDRFile. It is laid out as a camera with
+// EquirectangularProjection would see it from the origin: the image
+// center is toward -z, and its top straight up.
+type EnvironmentMap struct {
+	image *Canvas
+	// Cumulative distributions for importance sampling: of the rows, and
+	// of the pixels within each row, weighted by luminance and solid angle.
+	rowCDF []float64
+	colCDF [][]float64
+	// Sum of the weights; 0 for a black image, which is sampled evenly.
+	total float64
+}
+
+func NewEnvironmentMap(image *Canvas) *EnvironmentMap {
+	e := &EnvironmentMap{image: image, rowCDF: make([]float64, image.Height), colCDF: make([][]float64, image.Height)}
+	for y := 0; y < image.Height; y++ {
+		row := make([]float64, image.Width)
+		rowSum := 0.0
+		for x := 0; x < image.Width; x++ {
+			rowSum += e.weight(x, y)
+			row[x] = rowSum
+		}
+		e.colCDF[y] = row
+		e.total += rowSum
+		e.rowCDF[y] = e.total
+	}
+	return e
+}
+
+// Returns the relative chance of sampling pixel (x, y): its brightness
+// times the solid angle it covers.
+func (e *EnvironmentMap) weight(x, y int) float64 {
+	return e.image.Get(x, y).luminance() * math.Cos(e.latitude(float64(y)+0.5))
+}
+
+// Returns the latitude of canvas row coordinate py.
+func (e *EnvironmentMap) latitude(py float64) float64 {
+	return (0.5 - py/float64(e.image.Height)) * math.Pi
+}
+
+// Returns the image pixel seen in direction dir.
+func (e *EnvironmentMap) pixel(dir Vector) (x, y int) {
+	longitude := math.Atan2(-dir.X, -dir.Z)
+	latitude := math.Asin(math.Max(-1, math.Min(1, dir.Y)))
+	x = int((longitude/(2*math.Pi) + 0.5) * float64(e.image.Width))
+	y = int((0.5 - latitude/math.Pi) * float64(e.image.Height))
+	return clampInt(x, 0, e.image.Width-1), clampInt(y, 0, e.image.Height-1)
+}
+
+func (e *EnvironmentMap) Radiance(dir Vector) Color {
+	return e.image.Get(e.pixel(dir))
+}
+
+func (e *EnvironmentMap) Sample(u, v float64) (Vector, float64) {
+	if e.total == 0 {
+		return sampleSphere(u, v), 1 / (4 * math.Pi)
+	}
+	y, fy := sampleCDF(e.rowCDF, u)
+	x, fx := sampleCDF(e.colCDF[y], v)
+	longitude := ((float64(x)+fx)/float64(e.image.Width) - 0.5) * 2 * math.Pi
+	latitude := e.latitude(float64(y) + fy)
+	cosLat := math.Cos(latitude)
+	dir := Vector{-math.Sin(longitude) * cosLat, math.Sin(latitude), -math.Cos(longitude) * cosLat}
+	return dir, e.pixelPDF(x, y, latitude)
+}
+
+func (e *EnvironmentMap) PDF(dir Vector) float64 {
+	if e.total == 0 {
+		return 1 / (4 * math.Pi)
+	}
+	x, y := e.pixel(dir)
+	return e.pixelPDF(x, y, math.Asin(math.Max(-1, math.Min(1, dir.Y))))
+}
+
+// Returns the density per unit solid angle of samples at the given
+// latitude within pixel (x, y). Samples are spread evenly over the pixel
+// in the image, so more thinly per solid angle nearer the equator.
+func (e *EnvironmentMap) pixelPDF(x, y int, latitude float64) float64 {
+	w := e.weight(x, y)
+	cosLat := math.Cos(latitude)
+	if w == 0 || cosLat <= 0 {
+		return 0
+	}
+	pixels := float64(e.image.Width * e.image.Height)
+	return w / e.total * pixels / (2 * math.Pi * math.Pi * cosLat)
+}
+
+// Returns the index of the interval of cumulative distribution cdf that
+// u (in [0, 1)) falls into, and how far through the interval it falls.
+func sampleCDF(cdf []float64, u float64) (int, float64) {
+	target := u * cdf[len(cdf)-1]
+	i := sort.Search(len(cdf), func(i int) bool { return cdf[i] > target })
+	if i == len(cdf) {
+		i = len(cdf) - 1
+	}
+	lo := 0.0
+	if i > 0 {
+		lo = cdf[i-1]
+	}
+	if cdf[i] == lo {
+		return i, 0.5
+	}
+	return i, (target - lo) / (cdf[i] - lo)
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
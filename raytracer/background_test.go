@@ -0,0 +1,134 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBackgroundRadiance(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Background
+		dir  Vector
+		want Color
+	}{
+		{"solid", SolidBackground{Color{0.2, 0.4, 0.6}}, Vector{0, 1, 0}, Color{0.2, 0.4, 0.6}},
+		{"gradient up", GradientBackground{Black(), White()}, Vector{0, 1, 0}, White()},
+		{"gradient down", GradientBackground{Black(), White()}, Vector{0, -1, 0}, Black()},
+		{"gradient horizon", GradientBackground{Red(), Color{0, 0, 1}}, Vector{1, 0, 0}, Color{0.5, 0, 0.5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.b.Radiance(test.dir), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestEnvironmentMapRadiance(t *testing.T) {
+	image := MakeCanvas(4, 2)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			image.Set(x, y, Color{float64(x), float64(y), 1})
+		}
+	}
+	e := NewEnvironmentMap(image)
+	tests := []struct {
+		name string
+		dir  Vector
+		want Color
+	}{
+		{"ahead, above", Vector{0, 0.5, -0.86603}, Color{2, 0, 1}},
+		{"left, above", Vector{-0.86603, 0.5, 0}, Color{3, 0, 1}},
+		{"right, below", Vector{0.86603, -0.5, 0}, Color{1, 1, 1}},
+		{"behind, below", Vector{0.1, -0.5, 0.86}, Color{0, 1, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := e.Radiance(test.dir), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+// Returns an environment map whose pixels vary in brightness.
+func patternedEnvironment() *EnvironmentMap {
+	image := MakeCanvas(8, 4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			v := float64((x*3+y*5)%7) + 0.5
+			image.Set(x, y, Color{v, v, v})
+		}
+	}
+	return NewEnvironmentMap(image)
+}
+
+func TestEnvironmentMapSample(t *testing.T) {
+	e := patternedEnvironment()
+	n := 20
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			u, v := (float64(i)+0.37)/float64(n), (float64(j)+0.61)/float64(n)
+			dir, pdf := e.Sample(u, v)
+			if !approxEq(dir.Magnitude(), 1.0) {
+				t.Fatalf("Sample(%v, %v) direction %v is not a unit vector", u, v, dir)
+			}
+			if got := e.PDF(dir); !approxEq(got, pdf) {
+				t.Fatalf("Sample(%v, %v) pdf: %s", u, v, approxError(got, pdf))
+			}
+		}
+	}
+}
+
+func TestEnvironmentMapPDFNormalized(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Background
+	}{
+		{"solid", SolidBackground{White()}},
+		{"environment map", patternedEnvironment()},
+		{"black environment map", NewEnvironmentMap(MakeCanvas(8, 4))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Integrates the pdf over the sphere with evenly spread directions.
+			n := 200
+			sum := 0.0
+			for j := 0; j < n; j++ {
+				for i := 0; i < n; i++ {
+					sum += test.b.PDF(sampleSphere((float64(i)+0.5)/float64(n), (float64(j)+0.5)/float64(n)))
+				}
+			}
+			got := sum * 4 * math.Pi / float64(n*n)
+			if math.Abs(got-1) > 0.01 {
+				t.Errorf("pdf integrates to %v; want 1", got)
+			}
+		})
+	}
+}
+
+func TestEnvironmentMapSampleFavorsBrightPixels(t *testing.T) {
+	image := MakeCanvas(8, 4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			image.Set(x, y, Color{0.01, 0.01, 0.01})
+		}
+	}
+	image.Set(5, 1, Color{100, 100, 100})
+	e := NewEnvironmentMap(image)
+	rnd := newPixelRand(1, 0, 0)
+	hits, n := 0, 1000
+	for i := 0; i < n; i++ {
+		dir, _ := e.Sample(rnd.next(), rnd.next())
+		if x, y := e.pixel(dir); x == 5 && y == 1 {
+			hits++
+		}
+	}
+	if hits < n*9/10 {
+		t.Errorf("%d of %d samples fell in the bright pixel; want at least 90%%", hits, n)
+	}
+}
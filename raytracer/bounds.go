@@ -0,0 +1,114 @@
+package raytracer
+
+import "math"
+
+// Bounds is an axis-aligned bounding box.
+type Bounds struct {
+	Min, Max Point
+}
+
+// Returns bounds containing nothing, to be grown with add and union.
+func EmptyBounds() Bounds {
+	inf := math.Inf(1)
+	return Bounds{Point{inf, inf, inf}, Point{-inf, -inf, -inf}}
+}
+
+// Returns bounds containing everything, for unbounded shapes.
+func InfiniteBounds() Bounds {
+	inf := math.Inf(1)
+	return Bounds{Point{-inf, -inf, -inf}, Point{inf, inf, inf}}
+}
+
+func (b Bounds) isEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+func (b Bounds) isFinite() bool {
+	for _, v := range []float64{b.Min.X, b.Min.Y, b.Min.Z, b.Max.X, b.Max.Y, b.Max.Z} {
+		if math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns b grown to contain p.
+func (b Bounds) add(p Point) Bounds {
+	return Bounds{
+		Point{math.Min(b.Min.X, p.X), math.Min(b.Min.Y, p.Y), math.Min(b.Min.Z, p.Z)},
+		Point{math.Max(b.Max.X, p.X), math.Max(b.Max.Y, p.Y), math.Max(b.Max.Z, p.Z)},
+	}
+}
+
+// Returns bounds containing both b and b2.
+func (b Bounds) union(b2 Bounds) Bounds {
+	if b2.isEmpty() {
+		return b
+	}
+	return b.add(b2.Min).add(b2.Max)
+}
+
+// Returns axis-aligned bounds containing b transformed by m.
+func (b Bounds) xform(m *Matrix) Bounds {
+	if b.isEmpty() {
+		return b
+	}
+	if !b.isFinite() {
+		// Infinite extents can't be transformed corner by corner.
+		return InfiniteBounds()
+	}
+	xb := EmptyBounds()
+	for _, x := range []float64{b.Min.X, b.Max.X} {
+		for _, y := range []float64{b.Min.Y, b.Max.Y} {
+			for _, z := range []float64{b.Min.Z, b.Max.Z} {
+				xb = xb.add(m.TimesP(Point{x, y, z}))
+			}
+		}
+	}
+	return xb
+}
+
+func (b Bounds) centroid() Point {
+	return Point{(b.Min.X + b.Max.X) / 2, (b.Min.Y + b.Max.Y) / 2, (b.Min.Z + b.Max.Z) / 2}
+}
+
+func (b Bounds) surfaceArea() float64 {
+	if b.isEmpty() {
+		return 0
+	}
+	d := b.Max.Minus(b.Min)
+	return 2 * (d.X*d.Y + d.Y*d.Z + d.Z*d.X)
+}
+
+// Returns whether r passes through the box, or starts inside it.
+func (b Bounds) intersects(r Ray) bool {
+	if b.isEmpty() {
+		return false
+	}
+	tmin, tmax := math.Inf(-1), math.Inf(1)
+	for _, axis := range [][4]float64{
+		{r.orig.X, r.dir.X, b.Min.X, b.Max.X},
+		{r.orig.Y, r.dir.Y, b.Min.Y, b.Max.Y},
+		{r.orig.Z, r.dir.Z, b.Min.Z, b.Max.Z},
+	} {
+		orig, dir, min, max := axis[0], axis[1], axis[2], axis[3]
+		if math.Abs(dir) < epsilon {
+			if orig < min || orig > max {
+				return false
+			}
+			continue
+		}
+		t0 := (min - orig) / dir
+		t1 := (max - orig) / dir
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tmin = math.Max(tmin, t0)
+		tmax = math.Min(tmax, t1)
+		if tmin > tmax {
+			return false
+		}
+	}
+	// The box is entirely behind the ray's origin.
+	return tmax >= 0
+}
@@ -0,0 +1,135 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundsUnion(t *testing.T) {
+	b := EmptyBounds().add(Point{-5, 2, 0}).add(Point{7, 0, -3})
+	got, want := b.union(Bounds{Point{0, 0, 0}, Point{1, 4, 1}}), Bounds{Point{-5, 0, -3}, Point{7, 4, 1}}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+	if got := b.union(EmptyBounds()); !approxEq(got, b) {
+		t.Errorf("union with empty: %s", approxError(got, b))
+	}
+}
+
+func TestBoundsXform(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bounds
+		xf   *Matrix
+		want Bounds
+	}{
+		{
+			name: "rotated",
+			b:    Bounds{Point{-1, -1, -1}, Point{1, 1, 1}},
+			xf:   MakeRotationY(math.Pi / 4).RotateX(math.Pi / 4),
+			want: Bounds{Point{-1.41421, -1.70711, -1.70711}, Point{1.41421, 1.70711, 1.70711}},
+		},
+		{
+			name: "infinite",
+			b:    Bounds{Point{math.Inf(-1), 0, math.Inf(-1)}, Point{math.Inf(1), 0, math.Inf(1)}},
+			xf:   MakeTranslation(0, 1, 0),
+			want: InfiniteBounds(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.b.xform(test.xf), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestBoundsIntersects(t *testing.T) {
+	b := Bounds{Point{5, -2, 0}, Point{11, 4, 7}}
+	tests := []struct {
+		r    Ray
+		want bool
+	}{
+		{Ray{Point{15, 1, 2}, Vector{-1, 0, 0}, 0}, true},
+		{Ray{Point{-5, -1, 4}, Vector{1, 0, 0}, 0}, true},
+		{Ray{Point{7, 6, 5}, Vector{0, -1, 0}, 0}, true},
+		{Ray{Point{9, -5, 6}, Vector{0, 1, 0}, 0}, true},
+		{Ray{Point{8, 2, 12}, Vector{0, 0, -1}, 0}, true},
+		{Ray{Point{6, 0, -5}, Vector{0, 0, 1}, 0}, true},
+		{Ray{Point{8, 1, 3.5}, Vector{0, 0, 1}, 0}, true},
+		{Ray{Point{9, -1, -8}, Vector{2, 4, 6}.Norm(), 0}, false},
+		{Ray{Point{8, 3, -4}, Vector{6, 2, 4}.Norm(), 0}, false},
+		{Ray{Point{9, -1, -2}, Vector{4, 6, 2}.Norm(), 0}, false},
+		{Ray{Point{4, 0, 9}, Vector{0, 0, -1}, 0}, false},
+		{Ray{Point{8, 6, -1}, Vector{0, -1, 0}, 0}, false},
+		{Ray{Point{12, 5, 4}, Vector{-1, 0, 0}, 0}, false},
+		// Pointing away from the box.
+		{Ray{Point{15, 1, 2}, Vector{1, 0, 0}, 0}, false},
+		{Ray{Point{8, 1, 10}, Vector{0, 0.1, 1}.Norm(), 0}, false},
+	}
+	for _, test := range tests {
+		if got := b.intersects(test.r); got != test.want {
+			t.Errorf("intersects(%v) = %v; want %v", test.r, got, test.want)
+		}
+	}
+}
+
+func TestShapeBounds(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name string
+		s    Shape
+		want Bounds
+	}{
+		{
+			name: "sphere",
+			s:    NewSphere(MakeScaling(2, 2, 2).Translate(1, 0, 0)),
+			want: Bounds{Point{-1, -2, -2}, Point{3, 2, 2}},
+		},
+		{
+			name: "plane",
+			s:    NewPlane(MakeIdentity()),
+			want: Bounds{Point{-inf, 0, -inf}, Point{inf, 0, inf}},
+		},
+		{
+			name: "cube",
+			s:    NewCube(MakeIdentity()),
+			want: Bounds{Point{-1, -1, -1}, Point{1, 1, 1}},
+		},
+		{
+			name: "cylinder",
+			s:    NewCylinder(MakeIdentity(), -5, 3, true),
+			want: Bounds{Point{-1, -5, -1}, Point{1, 3, 1}},
+		},
+		{
+			name: "cone",
+			s:    NewCone(MakeIdentity(), -5, 3, true),
+			want: Bounds{Point{-5, -5, -5}, Point{5, 3, 5}},
+		},
+		{
+			name: "triangle",
+			s:    NewTriangle(Point{-3, 7, 2}, Point{6, 2, -4}, Point{2, -1, -1}),
+			want: Bounds{Point{-3, -1, -4}, Point{6, 7, 2}},
+		},
+		{
+			name: "group",
+			s: func() Shape {
+				g := NewGroup(MakeTranslation(0, 0, 10))
+				g.AddShape(NewSphere(MakeScaling(2, 2, 2).Translate(2, 5, -3)))
+				g.AddShape(NewCylinder(MakeScaling(0.5, 1, 0.5).Translate(-4, -1, 4), -2, 2, true))
+				return g
+			}(),
+			want: Bounds{Point{-4.5, -3, 5}, Point{4, 7, 14.5}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.s.Bounds(), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package raytracer
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Groups with at most this many bounded children are not split further.
+const bvhLeafSize = 4
+
+// Cost of testing a ray against a BVH node's bounds, relative to
+// intersecting one of its children.
+const bvhTraversalCost = 1.0
+
+// BVHStats describe a bounding volume hierarchy built by BuildBVH.
+type BVHStats struct {
+	BuildTime time.Duration
+	// Number of groups in the hierarchy, including the root.
+	Nodes int
+	// Number of groups on the longest path down from the root.
+	Depth int
+}
+
+// BuildBVH reorganizes the shapes under g, including those in child
+// groups, into a bounding volume hierarchy of nested groups. Splits are
+// chosen by the surface area heuristic, and rays skip any group whose
+// bounds they miss. Unbounded shapes such as planes stay where they are.
+func (g *Group) BuildBVH() BVHStats {
+	start := time.Now()
+	g.buildBVH()
+	stats := BVHStats{BuildTime: time.Since(start)}
+	stats.Nodes, stats.Depth = g.countNodes()
+	return stats
+}
+
+func (g *Group) buildBVH() {
+	for _, s := range g.shapes {
+		if sg, ok := s.(*Group); ok {
+			sg.buildBVH()
+		}
+	}
+	g.split()
+}
+
+// Moves g's bounded children into two new child groups, if the surface
+// area heuristic estimates that to be cheaper than testing each child,
+// and splits those groups in turn.
+func (g *Group) split() {
+	var bounded, unbounded []Shape
+	for _, s := range g.shapes {
+		if s.Bounds().isFinite() {
+			bounded = append(bounded, s)
+		} else {
+			unbounded = append(unbounded, s)
+		}
+	}
+	if len(bounded) <= bvhLeafSize {
+		return
+	}
+	left, right, ok := sahPartition(bounded)
+	if !ok {
+		return
+	}
+
+	g.shapes = unbounded
+	for _, part := range [][]Shape{left, right} {
+		if len(part) == 1 {
+			g.shapes = append(g.shapes, part[0])
+			continue
+		}
+		sub := NewGroup(MakeIdentity())
+		for _, s := range part {
+			sub.AddShape(s)
+		}
+		sub.split()
+		sub.setParent(g)
+		g.shapes = append(g.shapes, sub)
+	}
+}
+
+// Partitions shapes into two sets along the axis and position with the
+// lowest surface area heuristic cost. Returns false if no partition is
+// cheaper than leaving shapes together.
+func sahPartition(shapes []Shape) (left, right []Shape, ok bool) {
+	n := len(shapes)
+	bounds := make([]Bounds, n)
+	total := EmptyBounds()
+	for i, s := range shapes {
+		bounds[i] = s.Bounds()
+		total = total.union(bounds[i])
+	}
+	totalArea := total.surfaceArea()
+
+	bestCost := float64(n)
+	var best []int
+	bestSplit := 0
+	for axis := 0; axis < 3; axis++ {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return axisValue(bounds[order[i]].centroid(), axis) < axisValue(bounds[order[j]].centroid(), axis)
+		})
+
+		// rightArea[i] is the surface area of the bounds of order[i:].
+		rightArea := make([]float64, n)
+		b := EmptyBounds()
+		for i := n - 1; i > 0; i-- {
+			b = b.union(bounds[order[i]])
+			rightArea[i] = b.surfaceArea()
+		}
+		b = EmptyBounds()
+		for i := 1; i < n; i++ {
+			b = b.union(bounds[order[i-1]])
+			var cost float64
+			if totalArea > 0 {
+				cost = bvhTraversalCost + (b.surfaceArea()*float64(i)+rightArea[i]*float64(n-i))/totalArea
+			} else {
+				// Degenerate bounds; prefer an even split.
+				cost = bvhTraversalCost + math.Max(float64(i), float64(n-i))
+			}
+			if cost < bestCost {
+				bestCost = cost
+				best = order
+				bestSplit = i
+			}
+		}
+	}
+	if best == nil {
+		return nil, nil, false
+	}
+	for i, si := range best {
+		if i < bestSplit {
+			left = append(left, shapes[si])
+		} else {
+			right = append(right, shapes[si])
+		}
+	}
+	return left, right, true
+}
+
+// Returns the coordinate of p along axis 0 (x), 1 (y) or 2 (z).
+func axisValue(p Point, axis int) float64 {
+	switch axis {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	default:
+		return p.Z
+	}
+}
+
+// Returns the number of groups in the hierarchy under g, including g,
+// and the number of groups on the longest path down from g.
+func (g *Group) countNodes() (nodes, depth int) {
+	nodes = 1
+	for _, s := range g.shapes {
+		if sg, ok := s.(*Group); ok {
+			n, d := sg.countNodes()
+			nodes += n
+			if d > depth {
+				depth = d
+			}
+		}
+	}
+	return nodes, depth + 1
+}
+
+// Returns the mean number of groups whose bounds are tested when
+// intersecting each of rays with g, counting g itself. Rays are given in
+// the space of g's parent.
+func (g *Group) NodesVisitedPerRay(rays []Ray) float64 {
+	if len(rays) == 0 {
+		return 0
+	}
+	visits := 0
+	for _, r := range rays {
+		g.intersect(r, &visits)
+	}
+	return float64(visits) / float64(len(rays))
+}
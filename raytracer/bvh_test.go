@@ -0,0 +1,105 @@
+package raytracer
+
+import "testing"
+
+// Returns a group of n*n*n small spheres in a grid, plus a floor plane.
+func sphereGrid(n int) *Group {
+	g := NewGroup(MakeIdentity())
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			for z := 0; z < n; z++ {
+				xf := MakeScaling(0.3, 0.3, 0.3).Translate(float64(x), float64(y), float64(z))
+				g.AddShape(NewSphere(xf))
+			}
+		}
+	}
+	g.AddShape(NewPlane(MakeTranslation(0, -1, 0)))
+	return g
+}
+
+func gridRays(n int) []Ray {
+	rays := []Ray{}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			orig := Point{float64(x) - 0.5, float64(y) + 0.25, -5}
+			rays = append(rays, Ray{orig, Vector{0.1, -0.05, 1}.Norm(), 0})
+		}
+	}
+	return rays
+}
+
+func TestBuildBVHPreservesIntersections(t *testing.T) {
+	const n = 5
+	flat := sphereGrid(n)
+	bvh := sphereGrid(n)
+	stats := bvh.BuildBVH()
+
+	if stats.Nodes <= 1 {
+		t.Errorf("Nodes = %d; want > 1", stats.Nodes)
+	}
+	if stats.Depth <= 1 {
+		t.Errorf("Depth = %d; want > 1", stats.Depth)
+	}
+	for _, r := range gridRays(2 * n) {
+		got, want := bvh.Intersect(r), flat.Intersect(r)
+		if !approxEq(got, want) {
+			t.Errorf("ray %v: %s", r, approxError(got, want))
+		}
+		for i := range got {
+			if i < len(want) && !approxEq(got[i].normalV, want[i].normalV) {
+				t.Errorf("ray %v normal %d: %s", r, i, approxError(got[i].normalV, want[i].normalV))
+			}
+		}
+	}
+}
+
+func TestBuildBVHKeepsUnboundedShapesAtRoot(t *testing.T) {
+	g := sphereGrid(3)
+	g.BuildBVH()
+	found := false
+	for _, s := range g.Shapes() {
+		if _, ok := s.(*plane); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("plane not found among root shapes %v", g.Shapes())
+	}
+}
+
+func TestBuildBVHSmallGroupUnchanged(t *testing.T) {
+	g := NewGroup(MakeIdentity())
+	for i := 0; i < bvhLeafSize; i++ {
+		g.AddShape(NewSphere(MakeTranslation(float64(3*i), 0, 0)))
+	}
+	stats := g.BuildBVH()
+	if stats.Nodes != 1 || stats.Depth != 1 {
+		t.Errorf("got Nodes %d, Depth %d; want 1, 1", stats.Nodes, stats.Depth)
+	}
+	if len(g.Shapes()) != bvhLeafSize {
+		t.Errorf("got %d shapes; want %d", len(g.Shapes()), bvhLeafSize)
+	}
+}
+
+func TestNodesVisitedPerRay(t *testing.T) {
+	g := sphereGrid(6)
+	stats := g.BuildBVH()
+	got := g.NodesVisitedPerRay(gridRays(12))
+	if got < 1 || got >= float64(stats.Nodes) {
+		t.Errorf("NodesVisitedPerRay() = %f; want in [1, %d)", got, stats.Nodes)
+	}
+}
+
+func TestGroupSkipsChildrenWhenBoundsMissed(t *testing.T) {
+	g := NewGroup(MakeIdentity())
+	child := NewGroup(MakeIdentity())
+	child.AddShape(NewSphere(MakeIdentity()))
+	g.AddShape(child)
+	r := Ray{Point{0, 5, -5}, Vector{0, 0, 1}, 0}
+	if got := g.NodesVisitedPerRay([]Ray{r}); got != 1 {
+		t.Errorf("NodesVisitedPerRay() = %f; want 1, skipping the child", got)
+	}
+	if got := g.Intersect(r); len(got) != 0 {
+		t.Errorf("got %d intersections; want 0", len(got))
+	}
+}
@@ -1,109 +1,122 @@
 package raytracer
 
+import (
+	"errors"
+	"math"
+)
+
+// Describes a Camera. Renders a view of a scene onto a Canvas.
+// The camera sits at the origin of camera space looking toward -z;
+// its transform maps world space into camera space.
 type Camera struct {
 	Width, Height int
+	// Angle in radians spanned by the larger of Width and Height, for
+	// projections with a field of view.
+	FieldOfView float64
+	// Maps the canvas onto camera rays; nil means PerspectiveProjection{}.
+	Projection Projection
+	// Diameter of the lens in world units. 0 makes a pinhole camera, with
+	// everything in focus.
+	Aperture float64
+	// Distance from the camera, along its view direction, of the plane
+	// in sharp focus. Only used if Aperture is set, when it must be
+	// positive or rendering fails.
+	FocalDistance float64
+	// Times the shutter opens and closes. Each camera ray is cast at a
+	// time between them, so shapes moving meanwhile blur.
+	ShutterOpen, ShutterClose float64
+	xf                        *Matrix
+	ixf                       *Matrix
 }
 
-// Renders a view of the given World onto a canvas of the configured
-// size using the configured camera view.
-func (cam Camera) Render(s Shape, shader Shader) *Canvas {
-	c := MakeCanvas(cam.Width, cam.Height)
-	for y := 0; y < c.Height; y++ {
-		for x := 0; x < c.Width; x++ {
-			c.Set(x, y, shader.ColorAt(s, cam.rayForPixel(x, y)))
-		}
-	}
-	return c
+// NewCamera builds a camera of the given pixel size and field of view,
+// viewing the world through xf (usually from MakeViewTransform).
+func NewCamera(width, height int, fieldOfView float64, xf *Matrix) *Camera {
+	xf = xf.Copy()
+	return &Camera{Width: width, Height: height, FieldOfView: fieldOfView, xf: xf, ixf: xf.inverse()}
 }
 
-func (cam Camera) rayForPixel(x, y int) Ray {
-	cameraPos := Point{0, 0, -4}
-	filmPoint := cam.filmPointForPixel(x, y)
-	direction := filmPoint.Minus(cameraPos).Norm()
-	return Ray{cameraPos, direction}
+func (cam *Camera) Xform() *Matrix {
+	return cam.xf
 }
 
-// pixels [0..hPixels-1, 0..vPixels-1] map onto film of fieldOfView.
-// Film is at z=0, width and height set by fieldOfView from camera at origin.
-func (cam Camera) filmPointForPixel(x, y int) Point {
-	pZ := 0.0
-	pixelScale := 4.0 / float64(cam.Width)
-	middleX := float64(cam.Width-1) / 2.0
-	middleY := float64(cam.Height-1) / 2.0
-
-	pX := (float64(x) - middleX) * pixelScale
-	pY := (float64(cam.Height-1-y) - middleY) * pixelScale
-
-	return Point{pX, pY, pZ}
+// Returns a world-space camera ray through the film at the given canvas
+// coordinates, where pixel (x, y) covers [x, x+1) by [y, y+1), and false
+// if the projection has no ray there. The ray's time is chosen
+// using rnd from while the shutter is open. With a lens, the ray
+// leaves a point on the lens chosen using rnd and passes through the
+// point where the pinhole ray meets the focal plane, so only things at
+// FocalDistance are sharp. Rays that never reach the focal plane, from
+// projections wider than 180°, ignore the lens.
+func (cam *Camera) sampleRay(px, py float64, rnd *sampleRand) (Ray, bool) {
+	time := cam.ShutterOpen
+	if cam.ShutterClose > cam.ShutterOpen {
+		time += rnd.next() * (cam.ShutterClose - cam.ShutterOpen)
+	}
+	origin, dir, ok := cam.projection().Project(cam, px, py)
+	if cam.Aperture <= 0 || dir.Z >= 0 {
+		return cam.toWorld(origin, dir, time), ok
+	}
+	t := (cam.FocalDistance + origin.Z) / -dir.Z
+	focus := origin.PlusV(dir.Scale(t))
+	lx, ly := sampleDisk(rnd.next(), rnd.next())
+	r := cam.Aperture / 2
+	lens := Point{origin.X + lx*r, origin.Y + ly*r, origin.Z}
+	return cam.toWorld(lens, focus.Minus(lens), time), ok
 }
 
-/*
-// Describes a Camera. Renders a view of a World into a Canvas.
-@AutoValue
-public abstract class Camera {
-
-  // @param fromP camera location
-  // @param toP camera look-at location
-  // @param upV camera up direction
-  public static Camera create(
-      int hPixels, int vPixels, double fieldOfView, Tuple fromP, Tuple toP, Tuple upV) {
-    return new AutoValue_Camera(
-        hPixels, vPixels, fieldOfView, Matrix.viewTransform(fromP, toP, upV));
-  }
-
-  public abstract int hPixels();
-  public abstract int vPixels();
-  public abstract double fieldOfView();
-  public abstract Matrix transform();
-
-  protected Matrix inverseTransform() {
-    return transform().invert();
-  }
-
-  // Renders a view of the given World onto a canvas of the configured size using the
-  // configured camera view.
-  public Canvas render(World world) {
-    Canvas c = new Canvas(hPixels(), vPixels());
-    c.forEachIndex(
-        (x, y) -> {
-          c.setPixel(x, y, world.colorAt(rayForPixel(x, y)));
-        });
-    return c;
-  }
+// Returns the world-space ray from a camera-space origin and direction,
+// cast at the given time.
+func (cam *Camera) toWorld(origin Point, dir Vector, time float64) Ray {
+	worldOrigin := cam.ixf.TimesP(origin)
+	direction := cam.ixf.TimesP(origin.PlusV(dir)).Minus(worldOrigin).Norm()
+	return Ray{worldOrigin, direction, time}
+}
 
-  private static final Tuple CAMERA_POS = Tuple.point(0, 0, 0);
+// Returns an error describing why the camera's settings can't be
+// rendered, or nil.
+func (cam *Camera) validate() error {
+	if cam.Aperture > 0 && cam.FocalDistance <= 0 {
+		return errors.New("camera: FocalDistance must be positive when Aperture is set")
+	}
+	if v, ok := cam.projection().(projectionValidator); ok {
+		return v.validate()
+	}
+	return nil
+}
 
-  // Returns the camera ray that passes from the camera through the pixel at the given coordinates.
-  public Ray rayForPixel(int x, int y) {
-    Tuple filmPoint = filmPointForPixel(x, y);
-    Tuple direction = filmPoint.minus(CAMERA_POS).normalize();
+func (cam *Camera) projection() Projection {
+	if cam.Projection == nil {
+		return PerspectiveProjection{}
+	}
+	return cam.Projection
+}
 
-    return Ray.create(inverseTransform().times(CAMERA_POS), inverseTransform().times(direction));
-  }
+// Returns how big each pixel should be in camera-space units.
+func (cam *Camera) pixelScale() float64 {
+	filmSize := 2.0 * math.Tan(cam.FieldOfView/2) // film size at distance 1.
+	return filmSize / cam.maxPixels()
+}
 
-  // Returns how big each pixel should be in x/y-space units.
-  @Memoized
-  protected double pixelScale() {
-    double filmSize = 2.0 * Math.tan(fieldOfView() / 2); // film size at distance 1.
-    int maxPixels = Math.max(hPixels(), vPixels());
-    double pixelScale = filmSize / (maxPixels - 1);
-    return pixelScale;
-  }
+// Returns the larger of Width and Height.
+func (cam *Camera) maxPixels() float64 {
+	if cam.Height > cam.Width {
+		return float64(cam.Height)
+	}
+	return float64(cam.Width)
+}
 
-  // TODO: correctly map to pixel centers
-  // pixels [0..hPixels-1, 0..vPixels-1] map onto film of fieldOfView.
-  // Film is at z=-1, width and height set by fieldOfView from camera at origin.
-  public Tuple filmPointForPixel(int x, int y) {
-    // film Z plane.
-    final double PZ = -1.0;
-    final double pixelScale = pixelScale();
-    final double middleX = (hPixels() - 1) / 2.0;
-    final double middleY = (vPixels() - 1) / 2.0;
+// Canvas coordinates [0..Width, 0..Height] map onto film of FieldOfView.
+// Film is at z=-1, width and height set by FieldOfView from camera at origin.
+func (cam *Camera) filmPoint(px, py float64) Point {
+	pZ := -1.0
+	pixelScale := cam.pixelScale()
+	halfWidth := float64(cam.Width) * pixelScale / 2.0
+	halfHeight := float64(cam.Height) * pixelScale / 2.0
 
-    double pX = ((hPixels() - 1 - x) - middleX) * pixelScale;
-    double pY = ((vPixels() - 1 - y) - middleY) * pixelScale;
+	// Camera space x runs right to left as seen from the camera.
+	pX := halfWidth - px*pixelScale
+	pY := halfHeight - py*pixelScale
 
-    return Tuple.point(pX, pY, PZ);
-  }
+	return Point{pX, pY, pZ}
 }
-*/
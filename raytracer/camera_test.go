@@ -0,0 +1,145 @@
+package raytracer
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestCameraPixelScale(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{
+			name:   "horizontal canvas",
+			width:  200,
+			height: 125,
+		},
+		{
+			name:   "vertical canvas",
+			width:  125,
+			height: 200,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCamera(test.width, test.height, math.Pi/2, MakeIdentity())
+			got, want := c.pixelScale(), 0.01
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestCameraSampleRay(t *testing.T) {
+	tests := []struct {
+		name string
+		xf   *Matrix
+		x, y int
+		want Ray
+	}{
+		{
+			name: "through center of canvas",
+			xf:   MakeIdentity(),
+			x:    100,
+			y:    50,
+			want: Ray{Point{0, 0, 0}, Vector{0, 0, -1}, 0},
+		},
+		{
+			name: "through corner of canvas",
+			xf:   MakeIdentity(),
+			x:    0,
+			y:    0,
+			want: Ray{Point{0, 0, 0}, Vector{0.66519, 0.33259, -0.66851}, 0},
+		},
+		{
+			name: "when camera is transformed",
+			xf:   MakeTranslation(0, -2, 5).RotateY(math.Pi / 4),
+			x:    100,
+			y:    50,
+			want: Ray{Point{0, 2, -5}, Vector{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2}, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCamera(201, 101, math.Pi/2, test.xf)
+			got, ok := c.sampleRay(float64(test.x)+0.5, float64(test.y)+0.5, newPixelRand(1, test.x, test.y))
+			if want := test.want; !ok || !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestCameraSampleRayThinLens(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2, MakeTranslation(0, -2, 5).RotateY(math.Pi/4))
+	pinhole, _ := c.sampleRay(30.5, 70.5, newPixelRand(1, 30, 70))
+	c.Aperture = 0.5
+	c.FocalDistance = 4
+	// Where the pinhole ray meets the focal plane: 4 units along the view
+	// direction.
+	viewDir := Vector{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2}
+	focus := pinhole.position(4 / pinhole.dir.Dot(viewDir))
+
+	rnd := newPixelRand(1, 30, 70)
+	for i := 0; i < 20; i++ {
+		r, _ := c.sampleRay(30.5, 70.5, rnd)
+		if d := r.orig.Minus(pinhole.orig).Magnitude(); d > c.Aperture/2+epsilon {
+			t.Errorf("ray %d starts %v from the lens center; want at most %v", i, d, c.Aperture/2)
+		}
+		// Every lens ray passes through the same point on the focal plane.
+		got := r.position(focus.Minus(r.orig).Dot(r.dir))
+		if !approxEq(got, focus) {
+			t.Errorf("ray %d: %s", i, approxError(got, focus))
+		}
+	}
+}
+
+func TestCameraRenderLensWithoutFocalDistance(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2, MakeIdentity())
+	c.Aperture = 0.5
+	canvas, _, err := c.RenderContext(context.Background(), NewDefaultWorld(), RenderOptions{})
+	if canvas != nil || err == nil {
+		t.Errorf("got canvas %v, error %v; want nil and an error", canvas, err)
+	}
+	assertPanic(t, func() { c.Render(NewDefaultWorld()) }, "Render with no FocalDistance didn't panic")
+	c.FocalDistance = 4
+	if _, _, err := c.RenderContext(context.Background(), NewDefaultWorld(), RenderOptions{}); err != nil {
+		t.Errorf("with FocalDistance: got error %v; want nil", err)
+	}
+}
+
+func TestSampleDisk(t *testing.T) {
+	rnd := newPixelRand(3, 0, 0)
+	for i := 0; i < 100; i++ {
+		x, y := sampleDisk(rnd.next(), rnd.next())
+		if r := math.Hypot(x, y); r > 1 {
+			t.Fatalf("sampleDisk returned (%v, %v), %v from the center", x, y, r)
+		}
+	}
+	if x, y := sampleDisk(0.5, 0.5); x != 0 || y != 0 {
+		t.Errorf("sampleDisk(0.5, 0.5) = (%v, %v); want (0, 0)", x, y)
+	}
+	if x, y := sampleDisk(1, 0.5); !approxEq(x, 1.0) || !approxEq(y, 0.0) {
+		t.Errorf("sampleDisk(1, 0.5) = (%v, %v); want (1, 0)", x, y)
+	}
+}
+
+func TestCameraSampleRayShutter(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2, MakeIdentity())
+	if r, _ := c.sampleRay(5.5, 5.5, newPixelRand(1, 5, 5)); r.time != 0 {
+		t.Errorf("closed shutter: time = %v; want 0", r.time)
+	}
+	c.ShutterOpen, c.ShutterClose = 2, 3
+	rnd := newPixelRand(1, 5, 5)
+	min, max := math.Inf(1), math.Inf(-1)
+	for i := 0; i < 50; i++ {
+		r, _ := c.sampleRay(5.5, 5.5, rnd)
+		min, max = math.Min(min, r.time), math.Max(max, r.time)
+	}
+	if min < 2 || max >= 3 || max-min < 0.5 {
+		t.Errorf("times range over [%v, %v]; want spread over [2, 3)", min, max)
+	}
+}
@@ -1,6 +1,9 @@
 package raytracer
 
-import "image/color"
+import (
+	"image/color"
+	"math"
+)
 
 type Color struct {
 	R, G, B float64
@@ -52,3 +55,13 @@ func (c1 Color) times(c2 Color) Color {
 func (c Color) scale(s float64) Color {
 	return Color{s * c.R, s * c.G, s * c.B}
 }
+
+// Returns the perceived brightness of c (Rec. 709 weights).
+func (c Color) luminance() float64 {
+	return 0.2126*c.R + 0.7152*c.G + 0.0722*c.B
+}
+
+// Returns the largest of c's components.
+func (c Color) maxComponent() float64 {
+	return math.Max(c.R, math.Max(c.G, c.B))
+}
@@ -0,0 +1,96 @@
+package raytracer
+
+import (
+	"math"
+	"sort"
+)
+
+// A double-napped cone around the y axis with its apex at the origin,
+// in object space, where the radius at each y is |y|. It is truncated
+// to min < y < max and, if closed, the ends are capped.
+type cone struct {
+	shapeBase
+	min, max float64
+	closed   bool
+}
+
+// NewCone builds a cone spanning y from min to max in object space;
+// use math.Inf(-1) and math.Inf(1) for an infinite cone.
+func NewCone(m *Matrix, min, max float64, closed bool) Shape {
+	return &cone{newShapeBase(m), min, max, closed}
+}
+
+func (c *cone) Intersect(r Ray) []MaterialIntersection {
+	xs := []MaterialIntersection{}
+	xfray := r.xform(c.ixfAt(r.time))
+	for _, t := range c.intersectPoints(xfray) {
+		normalV := c.normalToWorld(c.localNormalAt(xfray.position(t)), r.time)
+		xs = append(xs, NewMaterialIntersection(r, t, normalV, c.material))
+	}
+	return xs
+}
+
+// Returns the t values where the object-space ray crosses the cone, in
+// increasing order.
+func (c *cone) intersectPoints(xfray Ray) []float64 {
+	o, d := xfray.orig, xfray.dir
+	ts := []float64{}
+
+	a := d.X*d.X - d.Y*d.Y + d.Z*d.Z
+	b := 2*o.X*d.X - 2*o.Y*d.Y + 2*o.Z*d.Z
+	c2 := o.X*o.X - o.Y*o.Y + o.Z*o.Z
+	var walls []float64
+	if math.Abs(a) < epsilon {
+		// Ray is parallel to one of the nappes, so crosses the other once.
+		if math.Abs(b) >= epsilon {
+			walls = []float64{-c2 / (2 * b)}
+		}
+	} else {
+		disc := b*b - 4*a*c2
+		if disc < 0 {
+			return ts
+		}
+		t0 := (-b - math.Sqrt(disc)) / (2 * a)
+		t1 := (-b + math.Sqrt(disc)) / (2 * a)
+		walls = []float64{t0, t1}
+	}
+	for _, t := range walls {
+		y := o.Y + t*d.Y
+		if c.min < y && y < c.max {
+			ts = append(ts, t)
+		}
+	}
+	if c.closed {
+		ts = append(ts, intersectCaps(xfray, c.min, math.Abs(c.min), c.max, math.Abs(c.max))...)
+	}
+	sort.Float64s(ts)
+	return ts
+}
+
+func (c *cone) Bounds() Bounds {
+	r := math.Max(math.Abs(c.min), math.Abs(c.max))
+	return c.parentBounds(Bounds{Point{-r, c.min, -r}, Point{r, c.max, r}})
+}
+
+func (c *cone) NormalAt(worldPoint Point) Vector {
+	return c.normalToWorld(c.localNormalAt(c.worldToObject(worldPoint, 0)), 0)
+}
+
+func (c *cone) localNormalAt(objectPoint Point) Vector {
+	dist := objectPoint.X*objectPoint.X + objectPoint.Z*objectPoint.Z
+
+	var objectNormal Vector
+	switch {
+	case dist < c.max*c.max && objectPoint.Y >= c.max-epsilon:
+		objectNormal = Vector{0, 1, 0}
+	case dist < c.min*c.min && objectPoint.Y <= c.min+epsilon:
+		objectNormal = Vector{0, -1, 0}
+	default:
+		y := math.Sqrt(dist)
+		if objectPoint.Y > 0 {
+			y = -y
+		}
+		objectNormal = Vector{objectPoint.X, y, objectPoint.Z}
+	}
+	return objectNormal
+}
@@ -0,0 +1,133 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConeIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "through apex",
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 5}, {t: 5}},
+		},
+		{
+			name: "along surface",
+			r:    Ray{Point{0, 0, -5}, Vector{1, 1, 1}.Norm(), 0},
+			want: []MaterialIntersection{{t: 8.66025}, {t: 8.66025}},
+		},
+		{
+			name: "through both nappes",
+			r:    Ray{Point{1, 1, -5}, Vector{-0.5, -1, 1}.Norm(), 0},
+			want: []MaterialIntersection{{t: 4.55006}, {t: 49.44994}},
+		},
+		{
+			name: "parallel to one nappe",
+			r:    Ray{Point{0, 0, -1}, Vector{0, 1, 1}.Norm(), 0},
+			want: []MaterialIntersection{{t: 0.35355}},
+		},
+		{
+			name: "along the axis through both nappes",
+			r:    Ray{Point{0.5, -3, 0}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{{t: 2.5}, {t: 3.5}},
+		},
+	}
+	c := NewCone(MakeIdentity(), math.Inf(-1), math.Inf(1), false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := c.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestCappedConeIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want int
+	}{
+		{
+			name: "parallel to axis, outside",
+			r:    Ray{Point{0, 0, -5}, Vector{0, 1, 0}, 0},
+			want: 0,
+		},
+		{
+			name: "through cap and wall",
+			r:    Ray{Point{0, 0, -0.25}, Vector{0, 1, 1}.Norm(), 0},
+			want: 2,
+		},
+		{
+			name: "through both caps and apex",
+			r:    Ray{Point{0, 0, -0.25}, Vector{0, 1, 0}, 0},
+			want: 4,
+		},
+	}
+	c := NewCone(MakeIdentity(), -0.5, 0.5, true)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := len(c.Intersect(test.r)), test.want
+			if got != want {
+				t.Errorf("got %d intersections; want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCappedConeIntersectionOrder(t *testing.T) {
+	c := NewCone(MakeIdentity(), -0.5, 0.5, true)
+	r := Ray{Point{0, -1, 0.25}, Vector{0, 1, 0}, 0}
+	got, want := c.Intersect(r), []MaterialIntersection{{t: 0.5}, {t: 0.75}, {t: 1.25}, {t: 1.5}}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestConeNormalAt(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Shape
+		p    Point
+		want Vector
+	}{
+		{
+			name: "upper nappe",
+			s:    NewCone(MakeIdentity(), math.Inf(-1), math.Inf(1), false),
+			p:    Point{1, 1, 1},
+			want: Vector{1, -math.Sqrt(2), 1}.Norm(),
+		},
+		{
+			name: "lower nappe",
+			s:    NewCone(MakeIdentity(), math.Inf(-1), math.Inf(1), false),
+			p:    Point{-1, -1, 0},
+			want: Vector{-1, 1, 0}.Norm(),
+		},
+		{
+			name: "top cap",
+			s:    NewCone(MakeIdentity(), -1, 1, true),
+			p:    Point{0.5, 1, 0},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "bottom cap",
+			s:    NewCone(MakeIdentity(), -1, 1, true),
+			p:    Point{0, -1, 0.5},
+			want: Vector{0, -1, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.s.NormalAt(test.p), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package raytracer
+
+import "sort"
+
+// How a CSG shape combines its two children.
+type CSGOperation int
+
+const (
+	// Everything in either child.
+	CSGUnion CSGOperation = iota
+	// Everything in both children.
+	CSGIntersection
+	// Everything in the left child but not the right.
+	CSGDifference
+)
+
+// A csg is a constructive solid geometry shape combining two children.
+type csg struct {
+	shapeBase
+	op          CSGOperation
+	left, right Shape
+}
+
+// NewCSG builds a shape combining left and right by op, with both
+// children transformed by m. Hits keep the material of the child they
+// came from; the CSG shape's own Material is not used, so changing it
+// has no effect.
+func NewCSG(m *Matrix, op CSGOperation, left, right Shape) Shape {
+	c := &csg{newShapeBase(m), op, left, right}
+	left.setParent(c)
+	right.setParent(c)
+	return c
+}
+
+// An intersection tagged with the child it came from.
+type csgIntersection struct {
+	MaterialIntersection
+	left bool
+}
+
+func (c *csg) Intersect(r Ray) []MaterialIntersection {
+	xfray := r
+	if !c.identity {
+		xfray = r.xform(c.ixfAt(r.time))
+	}
+	all := []csgIntersection{}
+	for _, x := range c.left.Intersect(xfray) {
+		all = append(all, csgIntersection{x, true})
+	}
+	for _, x := range c.right.Intersect(xfray) {
+		all = append(all, csgIntersection{x, false})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].t < all[j].t
+	})
+
+	// Walk the hits in order, tracking whether the ray is inside each
+	// child, and keep those on the surface of the combined shape.
+	xs := []MaterialIntersection{}
+	inLeft, inRight := false, false
+	for _, x := range all {
+		if c.allowed(x.left, inLeft, inRight) {
+			x.ray = r
+			if c.op == CSGDifference && !x.left {
+				// The right child's surface bounds the hole it cuts,
+				// so faces the other way.
+				x.normalV = x.normalV.Negate()
+			}
+			xs = append(xs, x.MaterialIntersection)
+		}
+		if x.left {
+			inLeft = !inLeft
+		} else {
+			inRight = !inRight
+		}
+	}
+	return xs
+}
+
+// Returns whether a hit on the left (or else right) child is on the
+// surface of the combined shape, given whether the ray is currently
+// inside each child.
+func (c *csg) allowed(leftHit, inLeft, inRight bool) bool {
+	switch c.op {
+	case CSGUnion:
+		return (leftHit && !inRight) || (!leftHit && !inLeft)
+	case CSGIntersection:
+		return (leftHit && inRight) || (!leftHit && inLeft)
+	case CSGDifference:
+		return (leftHit && !inRight) || (!leftHit && inLeft)
+	}
+	return false
+}
+
+func (c *csg) Bounds() Bounds {
+	b := c.left.Bounds()
+	if c.op != CSGDifference {
+		b = b.union(c.right.Bounds())
+	}
+	return c.parentBounds(b)
+}
+
+// Passes growth in a child's bounds up to any caching ancestor.
+func (c *csg) growBounds(b Bounds) {
+	if p, ok := c.parent.(boundsGrower); ok {
+		p.growBounds(c.Bounds())
+	}
+}
+
+// CSG shapes have no surface of their own; intersections carry the
+// normal of the child that was hit.
+func (c *csg) NormalAt(p Point) Vector {
+	return Vector{}
+}
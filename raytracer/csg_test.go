@@ -0,0 +1,155 @@
+package raytracer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCSGAllowed(t *testing.T) {
+	tests := []struct {
+		op                       CSGOperation
+		leftHit, inLeft, inRight bool
+		want                     bool
+	}{
+		{CSGUnion, true, true, true, false},
+		{CSGUnion, true, true, false, true},
+		{CSGUnion, true, false, true, false},
+		{CSGUnion, true, false, false, true},
+		{CSGUnion, false, true, true, false},
+		{CSGUnion, false, true, false, false},
+		{CSGUnion, false, false, true, true},
+		{CSGUnion, false, false, false, true},
+		{CSGIntersection, true, true, true, true},
+		{CSGIntersection, true, true, false, false},
+		{CSGIntersection, true, false, true, true},
+		{CSGIntersection, true, false, false, false},
+		{CSGIntersection, false, true, true, true},
+		{CSGIntersection, false, true, false, true},
+		{CSGIntersection, false, false, true, false},
+		{CSGIntersection, false, false, false, false},
+		{CSGDifference, true, true, true, false},
+		{CSGDifference, true, true, false, true},
+		{CSGDifference, true, false, true, false},
+		{CSGDifference, true, false, false, true},
+		{CSGDifference, false, true, true, true},
+		{CSGDifference, false, true, false, true},
+		{CSGDifference, false, false, true, false},
+		{CSGDifference, false, false, false, false},
+	}
+	for _, test := range tests {
+		c := NewCSG(MakeIdentity(), test.op, NewSphere(MakeIdentity()), NewCube(MakeIdentity())).(*csg)
+		if got := c.allowed(test.leftHit, test.inLeft, test.inRight); got != test.want {
+			t.Errorf("op %d allowed(%v, %v, %v) = %v; want %v",
+				test.op, test.leftHit, test.inLeft, test.inRight, got, test.want)
+		}
+	}
+}
+
+func TestCSGIntersection(t *testing.T) {
+	tests := []struct {
+		op       CSGOperation
+		want     []MaterialIntersection
+		wantLeft []bool
+	}{
+		{
+			op:       CSGUnion,
+			want:     []MaterialIntersection{{t: 4}, {t: 6.5}},
+			wantLeft: []bool{true, false},
+		},
+		{
+			op:       CSGIntersection,
+			want:     []MaterialIntersection{{t: 4.5}, {t: 6}},
+			wantLeft: []bool{false, true},
+		},
+		{
+			op:       CSGDifference,
+			want:     []MaterialIntersection{{t: 4}, {t: 4.5}},
+			wantLeft: []bool{true, false},
+		},
+	}
+	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("op %d", test.op), func(t *testing.T) {
+			left := NewSphere(MakeIdentity())
+			right := NewCube(MakeScaling(0.5, 0.5, 1).Translate(0, 0, 0.5))
+			c := NewCSG(MakeIdentity(), test.op, left, right)
+			got := c.Intersect(r)
+			if !approxEq(got, test.want) {
+				t.Fatal(approxError(got, test.want))
+			}
+			for i, x := range got {
+				want := right.Material()
+				if test.wantLeft[i] {
+					want = left.Material()
+				}
+				if x.material != want {
+					t.Errorf("intersection %d has material of wrong child", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCSGRayMisses(t *testing.T) {
+	c := NewCSG(MakeIdentity(), CSGUnion, NewSphere(MakeIdentity()), NewCube(MakeIdentity()))
+	got := c.Intersect(Ray{Point{0, 2, -5}, Vector{0, 0, 1}, 0})
+	if len(got) != 0 {
+		t.Errorf("got %d intersections; want 0", len(got))
+	}
+}
+
+func TestCSGDrilledBlock(t *testing.T) {
+	block := NewCube(MakeIdentity())
+	hole := NewCylinder(MakeScaling(0.5, 1, 0.5), -2, 2, true)
+	c := NewCSG(MakeTranslation(0, 0, 10), CSGDifference, block, hole)
+
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "down the hole",
+			r:    Ray{Point{0, 5, 10}, Vector{0, -1, 0}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "beside the hole",
+			r:    Ray{Point{0.75, 5, 10}, Vector{0, -1, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "across the hole",
+			r:    Ray{Point{-5, 0, 10}, Vector{1, 0, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 4.5}, {t: 5.5}, {t: 6}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := c.Intersect(test.r)
+			if !approxEq(got, test.want) {
+				t.Error(approxError(got, test.want))
+			}
+		})
+	}
+
+	// Normals on the walls of the hole face into it.
+	xs := c.Intersect(Ray{Point{-5, 0, 10}, Vector{1, 0, 0}, 0})
+	for i, want := range map[int]Vector{1: {1, 0, 0}, 2: {-1, 0, 0}} {
+		if got := xs[i].normalV; !approxEq(got, want) {
+			t.Errorf("hole normal %d: %s", i, approxError(got, want))
+		}
+	}
+}
+
+func TestCSGAsPlainShape(t *testing.T) {
+	c := NewCSG(MakeIdentity(), CSGUnion, NewSphere(MakeIdentity()), NewCube(MakeIdentity()))
+	assertNoPanic(t, func() {
+		if got, want := c.NormalAt(Point{1, 0, 0}), (Vector{}); got != want {
+			t.Errorf("NormalAt() = %v; want %v", got, want)
+		}
+	}, "NormalAt on a CSG shape panicked")
+	if c.Material() == nil {
+		t.Error("Material() = nil; want a material")
+	}
+}
@@ -0,0 +1,84 @@
+package raytracer
+
+import "math"
+
+// A cube is the axis-aligned box from (-1, -1, -1) to (1, 1, 1), in
+// object space.
+type cube struct {
+	shapeBase
+}
+
+func NewCube(m *Matrix) Shape {
+	return &cube{newShapeBase(m)}
+}
+
+func (c *cube) Intersect(r Ray) []MaterialIntersection {
+	xs := []MaterialIntersection{}
+	xfray := r.xform(c.ixfAt(r.time))
+	for _, t := range c.intersectPoints(xfray) {
+		normalV := c.normalToWorld(c.localNormalAt(xfray.position(t)), r.time)
+		xs = append(xs, NewMaterialIntersection(r, t, normalV, c.material))
+	}
+	return xs
+}
+
+// Intersects the ray with each pair of parallel faces (slabs); the ray
+// is inside the cube where it is inside all three slabs.
+func (c *cube) intersectPoints(xfray Ray) []float64 {
+	xtmin, xtmax := checkAxis(xfray.orig.X, xfray.dir.X)
+	ytmin, ytmax := checkAxis(xfray.orig.Y, xfray.dir.Y)
+	ztmin, ztmax := checkAxis(xfray.orig.Z, xfray.dir.Z)
+
+	tmin := math.Max(xtmin, math.Max(ytmin, ztmin))
+	tmax := math.Min(xtmax, math.Min(ytmax, ztmax))
+	if tmin > tmax {
+		return []float64{}
+	}
+	return []float64{tmin, tmax}
+}
+
+// Returns the range of t over which a ray with the given origin and
+// direction along one axis lies between -1 and 1 on that axis.
+func checkAxis(origin, direction float64) (float64, float64) {
+	tminNumerator := -1 - origin
+	tmaxNumerator := 1 - origin
+
+	var tmin, tmax float64
+	if math.Abs(direction) >= epsilon {
+		tmin = tminNumerator / direction
+		tmax = tmaxNumerator / direction
+	} else {
+		tmin = tminNumerator * math.Inf(1)
+		tmax = tmaxNumerator * math.Inf(1)
+	}
+	if tmin > tmax {
+		tmin, tmax = tmax, tmin
+	}
+	return tmin, tmax
+}
+
+func (c *cube) Bounds() Bounds {
+	return c.parentBounds(Bounds{Point{-1, -1, -1}, Point{1, 1, 1}})
+}
+
+func (c *cube) NormalAt(worldPoint Point) Vector {
+	return c.normalToWorld(c.localNormalAt(c.worldToObject(worldPoint, 0)), 0)
+}
+
+func (c *cube) localNormalAt(objectPoint Point) Vector {
+	absX := math.Abs(objectPoint.X)
+	absY := math.Abs(objectPoint.Y)
+	absZ := math.Abs(objectPoint.Z)
+
+	// The face is on the axis of largest magnitude.
+	var objectNormal Vector
+	switch math.Max(absX, math.Max(absY, absZ)) {
+	case absX:
+		objectNormal = Vector{objectPoint.X, 0, 0}
+	case absY:
+		objectNormal = Vector{0, objectPoint.Y, 0}
+	default:
+		objectNormal = Vector{0, 0, objectPoint.Z}
+	}
+	return objectNormal
+}
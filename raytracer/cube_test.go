@@ -0,0 +1,149 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCubeIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "+x",
+			r:    Ray{Point{5, 0.5, 0}, Vector{-1, 0, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "-x",
+			r:    Ray{Point{-5, 0.5, 0}, Vector{1, 0, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "+y",
+			r:    Ray{Point{0.5, 5, 0}, Vector{0, -1, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "-y",
+			r:    Ray{Point{0.5, -5, 0}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "+z",
+			r:    Ray{Point{0.5, 0, 5}, Vector{0, 0, -1}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "-z",
+			r:    Ray{Point{0.5, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "inside",
+			r:    Ray{Point{0, 0.5, 0}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: -1}, {t: 1}},
+		},
+		{
+			name: "misses diagonally",
+			r:    Ray{Point{-2, 0, 0}, Vector{0.2673, 0.5345, 0.8018}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses parallel to a face",
+			r:    Ray{Point{2, 2, 0}, Vector{-1, 0, 0}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses outside a slab",
+			r:    Ray{Point{0, 2, 2}, Vector{0, 0, -1}, 0},
+			want: []MaterialIntersection{},
+		},
+	}
+	c := NewCube(MakeIdentity())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := c.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestTransformedCubeIntersection(t *testing.T) {
+	c := NewCube(MakeScaling(2, 1, 1).Translate(0, 0, 5))
+	r := Ray{Point{-5, 0, 5}, Vector{1, 0, 0}, 0}
+	got, want := c.Intersect(r), []MaterialIntersection{{t: 3}, {t: 7}}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestCubeNormalAt(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Shape
+		p    Point
+		want Vector
+	}{
+		{
+			name: "+x face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{1, 0.5, -0.8},
+			want: Vector{1, 0, 0},
+		},
+		{
+			name: "-x face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{-1, -0.2, 0.9},
+			want: Vector{-1, 0, 0},
+		},
+		{
+			name: "+y face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{-0.4, 1, -0.1},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "-y face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{0.3, -1, -0.7},
+			want: Vector{0, -1, 0},
+		},
+		{
+			name: "+z face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{-0.6, 0.3, 1},
+			want: Vector{0, 0, 1},
+		},
+		{
+			name: "-z face",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{0.4, 0.4, -1},
+			want: Vector{0, 0, -1},
+		},
+		{
+			name: "+x corner",
+			s:    NewCube(MakeIdentity()),
+			p:    Point{1, 1, 1},
+			want: Vector{1, 0, 0},
+		},
+		{
+			name: "on rotated cube",
+			s:    NewCube(MakeRotationY(math.Pi / 4)),
+			p:    Point{math.Sqrt(2) / 2, 0, math.Sqrt(2) / 2},
+			want: Vector{math.Sqrt(2) / 2, 0, math.Sqrt(2) / 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.s.NormalAt(test.p), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package raytracer
+
+import (
+	"math"
+	"sort"
+)
+
+// A cylinder of radius 1 around the y axis, in object space, truncated
+// to min < y < max. If closed, the ends are capped.
+type cylinder struct {
+	shapeBase
+	min, max float64
+	closed   bool
+}
+
+// NewCylinder builds a cylinder spanning y from min to max in object
+// space; use math.Inf(-1) and math.Inf(1) for an infinite cylinder.
+func NewCylinder(m *Matrix, min, max float64, closed bool) Shape {
+	return &cylinder{newShapeBase(m), min, max, closed}
+}
+
+func (c *cylinder) Intersect(r Ray) []MaterialIntersection {
+	xs := []MaterialIntersection{}
+	xfray := r.xform(c.ixfAt(r.time))
+	for _, t := range c.intersectPoints(xfray) {
+		normalV := c.normalToWorld(c.localNormalAt(xfray.position(t)), r.time)
+		xs = append(xs, NewMaterialIntersection(r, t, normalV, c.material))
+	}
+	return xs
+}
+
+// Returns the t values where the object-space ray crosses the cylinder,
+// in increasing order.
+func (c *cylinder) intersectPoints(xfray Ray) []float64 {
+	o, d := xfray.orig, xfray.dir
+	ts := []float64{}
+
+	a := d.X*d.X + d.Z*d.Z
+	// Rays parallel to the y axis can only hit the caps.
+	if math.Abs(a) >= epsilon {
+		b := 2*o.X*d.X + 2*o.Z*d.Z
+		c2 := o.X*o.X + o.Z*o.Z - 1
+		disc := b*b - 4*a*c2
+		if disc < 0 {
+			return ts
+		}
+		t0 := (-b - math.Sqrt(disc)) / (2 * a)
+		t1 := (-b + math.Sqrt(disc)) / (2 * a)
+		for _, t := range []float64{t0, t1} {
+			y := o.Y + t*d.Y
+			if c.min < y && y < c.max {
+				ts = append(ts, t)
+			}
+		}
+	}
+	if c.closed {
+		ts = append(ts, intersectCaps(xfray, c.min, 1, c.max, 1)...)
+	}
+	sort.Float64s(ts)
+	return ts
+}
+
+// Returns the t values where the ray crosses the end caps at y = min
+// and y = max, of the given radii, in object space.
+func intersectCaps(r Ray, min, minRadius, max, maxRadius float64) []float64 {
+	ts := []float64{}
+	if math.Abs(r.dir.Y) < epsilon {
+		return ts
+	}
+	for _, cap := range []struct{ y, radius float64 }{{min, minRadius}, {max, maxRadius}} {
+		t := (cap.y - r.orig.Y) / r.dir.Y
+		x := r.orig.X + t*r.dir.X
+		z := r.orig.Z + t*r.dir.Z
+		if x*x+z*z <= cap.radius*cap.radius {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
+
+func (c *cylinder) Bounds() Bounds {
+	return c.parentBounds(Bounds{Point{-1, c.min, -1}, Point{1, c.max, 1}})
+}
+
+func (c *cylinder) NormalAt(worldPoint Point) Vector {
+	return c.normalToWorld(c.localNormalAt(c.worldToObject(worldPoint, 0)), 0)
+}
+
+func (c *cylinder) localNormalAt(objectPoint Point) Vector {
+	dist := objectPoint.X*objectPoint.X + objectPoint.Z*objectPoint.Z
+
+	var objectNormal Vector
+	switch {
+	case dist < 1 && objectPoint.Y >= c.max-epsilon:
+		objectNormal = Vector{0, 1, 0}
+	case dist < 1 && objectPoint.Y <= c.min+epsilon:
+		objectNormal = Vector{0, -1, 0}
+	default:
+		objectNormal = Vector{objectPoint.X, 0, objectPoint.Z}
+	}
+	return objectNormal
+}
@@ -0,0 +1,227 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCylinderIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "misses, outside and parallel",
+			r:    Ray{Point{1, 0, 0}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses, inside and parallel",
+			r:    Ray{Point{0, 0, 0}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses, skew",
+			r:    Ray{Point{0, 0, -5}, Vector{1, 1, 1}.Norm(), 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "tangent",
+			r:    Ray{Point{1, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 5}, {t: 5}},
+		},
+		{
+			name: "through middle",
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 4}, {t: 6}},
+		},
+		{
+			name: "at an angle",
+			r:    Ray{Point{0.5, 0, -5}, Vector{0.1, 1, 1}.Norm(), 0},
+			want: []MaterialIntersection{{t: 6.80798}, {t: 7.08872}},
+		},
+	}
+	c := NewCylinder(MakeIdentity(), math.Inf(-1), math.Inf(1), false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := c.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestTruncatedCylinderIntersection(t *testing.T) {
+	tests := []struct {
+		name   string
+		closed bool
+		r      Ray
+		want   int
+	}{
+		{
+			name: "diagonal from inside escapes",
+			r:    Ray{Point{0, 1.5, 0}, Vector{0.1, 1, 0}.Norm(), 0},
+			want: 0,
+		},
+		{
+			name: "passes above",
+			r:    Ray{Point{0, 3, -5}, Vector{0, 0, 1}, 0},
+			want: 0,
+		},
+		{
+			name: "passes below",
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: 0,
+		},
+		{
+			name: "hits max edge exactly",
+			r:    Ray{Point{0, 2, -5}, Vector{0, 0, 1}, 0},
+			want: 0,
+		},
+		{
+			name: "hits min edge exactly",
+			r:    Ray{Point{0, 1, -5}, Vector{0, 0, 1}, 0},
+			want: 0,
+		},
+		{
+			name: "through middle",
+			r:    Ray{Point{0, 1.5, -2}, Vector{0, 0, 1}, 0},
+			want: 2,
+		},
+		{
+			name:   "capped, through both caps",
+			closed: true,
+			r:      Ray{Point{0, 3, 0}, Vector{0, -1, 0}, 0},
+			want:   2,
+		},
+		{
+			name:   "capped, through top cap and wall",
+			closed: true,
+			r:      Ray{Point{0, 3, -2}, Vector{0, -1, 2}.Norm(), 0},
+			want:   2,
+		},
+		{
+			name:   "capped, through top cap corner",
+			closed: true,
+			r:      Ray{Point{0, 4, -2}, Vector{0, -1, 1}.Norm(), 0},
+			want:   2,
+		},
+		{
+			name:   "capped, through bottom cap and wall",
+			closed: true,
+			r:      Ray{Point{0, 0, -2}, Vector{0, 1, 2}.Norm(), 0},
+			want:   2,
+		},
+		{
+			name:   "capped, through bottom cap corner",
+			closed: true,
+			r:      Ray{Point{0, -1, -2}, Vector{0, 1, 1}.Norm(), 0},
+			want:   2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCylinder(MakeIdentity(), 1, 2, test.closed)
+			got, want := len(c.Intersect(test.r)), test.want
+			if got != want {
+				t.Errorf("got %d intersections; want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCappedCylinderIntersectionOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "cap then wall",
+			r:    Ray{Point{0, 2.5, 0}, Vector{1, -1, 0}.Norm(), 0},
+			want: []MaterialIntersection{{t: 0.70711}, {t: 1.41421}},
+		},
+		{
+			name: "wall then cap",
+			r:    Ray{Point{0, 1.2, -2}, Vector{0, 1, 2}.Norm(), 0},
+			want: []MaterialIntersection{{t: 1.11803}, {t: 1.78885}},
+		},
+	}
+	c := NewCylinder(MakeIdentity(), 1, 2, true)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := c.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestCylinderNormalAt(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Shape
+		p    Point
+		want Vector
+	}{
+		{
+			name: "+x wall",
+			s:    NewCylinder(MakeIdentity(), math.Inf(-1), math.Inf(1), false),
+			p:    Point{1, 0, 0},
+			want: Vector{1, 0, 0},
+		},
+		{
+			name: "-z wall",
+			s:    NewCylinder(MakeIdentity(), math.Inf(-1), math.Inf(1), false),
+			p:    Point{0, 5, -1},
+			want: Vector{0, 0, -1},
+		},
+		{
+			name: "-x wall",
+			s:    NewCylinder(MakeIdentity(), math.Inf(-1), math.Inf(1), false),
+			p:    Point{-1, 1, 0},
+			want: Vector{-1, 0, 0},
+		},
+		{
+			name: "bottom cap center",
+			s:    NewCylinder(MakeIdentity(), 1, 2, true),
+			p:    Point{0, 1, 0},
+			want: Vector{0, -1, 0},
+		},
+		{
+			name: "bottom cap",
+			s:    NewCylinder(MakeIdentity(), 1, 2, true),
+			p:    Point{0.5, 1, 0},
+			want: Vector{0, -1, 0},
+		},
+		{
+			name: "top cap center",
+			s:    NewCylinder(MakeIdentity(), 1, 2, true),
+			p:    Point{0, 2, 0},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "top cap",
+			s:    NewCylinder(MakeIdentity(), 1, 2, true),
+			p:    Point{0, 2, 0.5},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "on scaled cylinder",
+			s:    NewCylinder(MakeScaling(2, 1, 2), math.Inf(-1), math.Inf(1), false),
+			p:    Point{0, 0, 2},
+			want: Vector{0, 0, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.s.NormalAt(test.p), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
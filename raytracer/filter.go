@@ -0,0 +1,99 @@
+package raytracer
+
+import "math"
+
+// A Filter reconstructs a pixel from samples by weighting each by its
+// offset (dx, dy) from the pixel center, in pixels.
+type Filter interface {
+	// Half-width of the square the filter covers, in pixels. Samples for
+	// a pixel are spread over this square.
+	Radius() float64
+	Weight(dx, dy float64) float64
+}
+
+// BoxFilter weights samples within the pixel equally.
+type BoxFilter struct{}
+
+func (f BoxFilter) Radius() float64 {
+	return 0.5
+}
+
+func (f BoxFilter) Weight(dx, dy float64) float64 {
+	return 1
+}
+
+// TentFilter weights samples falling off linearly to zero at R pixels
+// from the center; R <= 0 means 1.
+type TentFilter struct {
+	R float64
+}
+
+func (f TentFilter) Radius() float64 {
+	return defaultIfNotPositive(f.R, 1)
+}
+
+func (f TentFilter) Weight(dx, dy float64) float64 {
+	r := f.Radius()
+	return math.Max(0, 1-math.Abs(dx)/r) * math.Max(0, 1-math.Abs(dy)/r)
+}
+
+// GaussianFilter weights samples by a Gaussian of falloff Alpha,
+// shifted to reach zero at R pixels from the center. R <= 0 means 1.5,
+// and Alpha <= 0 means 2.
+type GaussianFilter struct {
+	R, Alpha float64
+}
+
+func (f GaussianFilter) Radius() float64 {
+	return defaultIfNotPositive(f.R, 1.5)
+}
+
+func (f GaussianFilter) Weight(dx, dy float64) float64 {
+	return f.gaussian(dx) * f.gaussian(dy)
+}
+
+func (f GaussianFilter) gaussian(d float64) float64 {
+	r, alpha := f.Radius(), defaultIfNotPositive(f.Alpha, 2)
+	return math.Max(0, math.Exp(-alpha*d*d)-math.Exp(-alpha*r*r))
+}
+
+// MitchellFilter is the Mitchell-Netravali cubic filter of radius R with
+// parameters B and C; B = C = 1/3 is the usual choice. Its negative
+// lobes sharpen edges. R <= 0 means 2, and B and C both 0 mean 1/3.
+type MitchellFilter struct {
+	R, B, C float64
+}
+
+func (f MitchellFilter) Radius() float64 {
+	return defaultIfNotPositive(f.R, 2)
+}
+
+func (f MitchellFilter) Weight(dx, dy float64) float64 {
+	r := f.Radius()
+	return f.mitchell(2*dx/r) * f.mitchell(2*dy/r)
+}
+
+// The one-dimensional filter over [-2, 2].
+func (f MitchellFilter) mitchell(x float64) float64 {
+	b, c := f.B, f.C
+	if b == 0 && c == 0 {
+		b, c = 1.0/3, 1.0/3
+	}
+	x = math.Abs(x)
+	switch {
+	case x < 1:
+		return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+	case x < 2:
+		return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+	}
+	return 0
+}
+
+// Returns v, or def if v is not positive, for filter parameters whose
+// zero value is unusable.
+func defaultIfNotPositive(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,63 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFilterWeights(t *testing.T) {
+	tests := []struct {
+		name   string
+		f      Filter
+		dx, dy float64
+		want   float64
+	}{
+		{"box center", BoxFilter{}, 0, 0, 1},
+		{"box edge", BoxFilter{}, 0.4, -0.4, 1},
+		{"tent center", TentFilter{1}, 0, 0, 1},
+		{"tent halfway", TentFilter{1}, 0.5, 0, 0.5},
+		{"tent both axes", TentFilter{1}, 0.5, -0.5, 0.25},
+		{"tent outside", TentFilter{1}, 1.5, 0, 0},
+		{"gaussian center", GaussianFilter{1.5, 2}, 0, 0, math.Pow(1-math.Exp(-4.5), 2)},
+		{"gaussian edge", GaussianFilter{1.5, 2}, 1.5, 0, 0},
+		{"mitchell center", MitchellFilter{2, 1.0 / 3, 1.0 / 3}, 0, 0, math.Pow(8.0/9, 2)},
+		{"mitchell edge", MitchellFilter{2, 1.0 / 3, 1.0 / 3}, 2, 0, 0},
+		{"tent zero value", TentFilter{}, 0.5, 0, 0.5},
+		{"gaussian zero value", GaussianFilter{}, 0, 0, math.Pow(1-math.Exp(-4.5), 2)},
+		{"mitchell zero value", MitchellFilter{}, 0, 0, math.Pow(8.0/9, 2)},
+		{"mitchell zero value off center", MitchellFilter{}, 1, 1, math.Pow(1.0/18, 2)},
+		{"mitchell negative radius", MitchellFilter{-1, 1.0 / 3, 1.0 / 3}, 2, 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.f.Weight(test.dx, test.dy)
+			if !approxEq(got, test.want) {
+				t.Error(approxError(got, test.want))
+			}
+		})
+	}
+}
+
+func TestFilterDefaultRadius(t *testing.T) {
+	tests := []struct {
+		f    Filter
+		want float64
+	}{
+		{TentFilter{}, 1},
+		{GaussianFilter{}, 1.5},
+		{MitchellFilter{}, 2},
+		{MitchellFilter{R: 3}, 3},
+	}
+	for _, test := range tests {
+		if got := test.f.Radius(); got != test.want {
+			t.Errorf("%#v.Radius() = %v; want %v", test.f, got, test.want)
+		}
+	}
+}
+
+func TestMitchellFilterNegativeLobe(t *testing.T) {
+	f := MitchellFilter{2, 1.0 / 3, 1.0 / 3}
+	if got := f.Weight(1.5, 0); got >= 0 {
+		t.Errorf("Weight(1.5, 0) = %v; want < 0", got)
+	}
+}
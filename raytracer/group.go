@@ -2,19 +2,52 @@ package raytracer
 
 import "sort"
 
+// A Group is a Shape made up of other shapes, transformed together.
 type Group struct {
+	shapeBase
 	shapes []Shape
+	// Bounds of the children, in the group's own space.
+	localBounds Bounds
 }
 
-	Intersect(r Ray) []MaterialIntersection
-	Xform() *Matrix
-	NormalAt(p Point) Vector
-	Material() *Material
+// NewGroup builds an empty group whose children are transformed by m.
+// Each child is drawn with its own material; the group's Material is
+// not used, so changing it has no effect. To restyle a loaded mesh, set
+// ObjFile.Material instead.
+func NewGroup(m *Matrix) *Group {
+	return &Group{shapeBase: newShapeBase(m), shapes: []Shape{}, localBounds: EmptyBounds()}
+}
+
+func (g *Group) Intersect(r Ray) []MaterialIntersection {
+	return g.intersect(r, nil)
+}
 
-func (w *world) Intersect(r Ray) []MaterialIntersection {
+// Intersects r with the group as Intersect does, adding to visits, if it
+// is not nil, the number of groups whose bounds are tested: g, and any
+// descendant groups reached directly through groups.
+func (g *Group) intersect(r Ray, visits *int) []MaterialIntersection {
+	if visits != nil {
+		*visits++
+	}
+	xfray := r
+	if !g.identity {
+		xfray = r.xform(g.ixfAt(r.time))
+	}
 	xs := []MaterialIntersection{}
-	for _, s := range w.shapes {
-		xs = append(xs, s.Intersect(r)...)
+	if !g.localBounds.intersects(xfray) {
+		return xs
+	}
+	for _, s := range g.shapes {
+		if sg, ok := s.(*Group); ok {
+			xs = append(xs, sg.intersect(xfray, visits)...)
+		} else {
+			xs = append(xs, s.Intersect(xfray)...)
+		}
+	}
+	// t is unchanged by the transform, so report hits against the ray
+	// as given.
+	for i := range xs {
+		xs[i].ray = r
 	}
 	sort.Slice(xs, func(i, j int) bool {
 		return xs[i].t < xs[j].t
@@ -22,10 +55,37 @@ func (w *world) Intersect(r Ray) []MaterialIntersection {
 	return xs
 }
 
+func (g *Group) Bounds() Bounds {
+	return g.parentBounds(g.localBounds)
+}
+
+// Groups have no surface of their own; intersections carry the normal
+// of the child that was hit.
+func (g *Group) NormalAt(p Point) Vector {
+	return Vector{}
+}
+
+// Adds s to the group, making the group its parent. A shape belongs to
+// at most one group.
 func (g *Group) AddShape(s Shape) {
+	s.setParent(g)
 	g.shapes = append(g.shapes, s)
+	g.growBounds(s.Bounds())
+}
+
+// Implemented by shapes whose bounds depend on their descendants.
+type boundsGrower interface {
+	growBounds(b Bounds)
+}
+
+// Grows the group's bounds, and those of its ancestors, to contain b.
+func (g *Group) growBounds(b Bounds) {
+	g.localBounds = g.localBounds.union(b)
+	if p, ok := g.parent.(boundsGrower); ok {
+		p.growBounds(g.Bounds())
+	}
 }
 
-func NewGroup() *Group {
-	return &Group{[]Shape{}}
+func (g *Group) Shapes() []Shape {
+	return g.shapes
 }
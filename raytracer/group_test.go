@@ -0,0 +1,137 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroupAddShape(t *testing.T) {
+	g := NewGroup(MakeIdentity())
+	s := NewSphere(MakeIdentity())
+	g.AddShape(s)
+	if len(g.Shapes()) != 1 || g.Shapes()[0] != s {
+		t.Errorf("Shapes() = %v; want [%v]", g.Shapes(), s)
+	}
+	if s.Parent() != g {
+		t.Errorf("Parent() = %v; want %v", s.Parent(), g)
+	}
+}
+
+func TestGroupIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		g    func() *Group
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "empty group",
+			g:    func() *Group { return NewGroup(MakeIdentity()) },
+			r:    Ray{Point{0, 0, 0}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "nonempty group",
+			g: func() *Group {
+				g := NewGroup(MakeIdentity())
+				g.AddShape(NewSphere(MakeIdentity()))
+				g.AddShape(NewSphere(MakeTranslation(0, 0, -3)))
+				g.AddShape(NewSphere(MakeTranslation(5, 0, 0)))
+				return g
+			},
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 1}, {t: 3}, {t: 4}, {t: 6}},
+		},
+		{
+			name: "transformed group",
+			g: func() *Group {
+				g := NewGroup(MakeScaling(2, 2, 2))
+				g.AddShape(NewSphere(MakeTranslation(5, 0, 0)))
+				return g
+			},
+			r:    Ray{Point{10, 0, -10}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 8}, {t: 12}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.g().Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+			for _, x := range got {
+				if !approxEq(x.ray, test.r) {
+					t.Errorf("intersection ray: %s", approxError(x.ray, test.r))
+				}
+			}
+		})
+	}
+}
+
+// Returns a sphere translated by (5, 0, 0) inside a group transformed
+// by xf inside a group rotated around y by pi/2.
+func nestedSphere(xf *Matrix) *sphere {
+	g1 := NewGroup(MakeRotationY(math.Pi / 2))
+	g2 := NewGroup(xf)
+	g1.AddShape(g2)
+	s := NewSphere(MakeTranslation(5, 0, 0))
+	g2.AddShape(s)
+	return s.(*sphere)
+}
+
+func TestShapeWorldToObject(t *testing.T) {
+	got, want := nestedSphere(MakeScaling(2, 2, 2)).worldToObject(Point{-2, 0, -10}, 0), Point{0, 0, -1}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestShapeNormalToWorld(t *testing.T) {
+	n := Vector{math.Sqrt(3) / 3, math.Sqrt(3) / 3, math.Sqrt(3) / 3}
+	got, want := nestedSphere(MakeScaling(1, 2, 3)).normalToWorld(n, 0), Vector{0.28571, 0.42857, -0.85714}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestGroupChildNormalAt(t *testing.T) {
+	got, want := nestedSphere(MakeScaling(1, 2, 3)).NormalAt(Point{1.7321, 1.1547, -5.5774}), Vector{0.28570, 0.42854, -0.85716}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestGroupChildIntersectionNormal(t *testing.T) {
+	s := nestedSphere(MakeScaling(1, 2, 3))
+	var root Shape = s
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	// Aim at the world-space point used in TestGroupChildNormalAt.
+	p := Point{1.7321, 1.1547, -5.5774}
+	want := Vector{0.28570, 0.42854, -0.85716}
+	r := Ray{p.PlusV(want.Scale(2)), want.Negate(), 0}
+	x := hit(Intersections(root.Intersect(r)))
+	if x == nil {
+		t.Fatal("got no hit; want hit")
+	}
+	if !approxEq(x.point, p) {
+		t.Errorf("point: %s", approxError(x.point, p))
+	}
+	if !approxEq(x.normalV, want) {
+		t.Errorf("normal: %s", approxError(x.normalV, want))
+	}
+}
+
+func TestGroupAsPlainShape(t *testing.T) {
+	var g Shape = NewGroup(MakeTranslation(1, 0, 0))
+	g.(*Group).AddShape(NewSphere(MakeIdentity()))
+	assertNoPanic(t, func() {
+		if got, want := g.NormalAt(Point{2, 0, 0}), (Vector{}); got != want {
+			t.Errorf("NormalAt() = %v; want %v", got, want)
+		}
+	}, "NormalAt on a Group panicked")
+	if g.Material() == nil {
+		t.Error("Material() = nil; want a material")
+	}
+}
@@ -0,0 +1,154 @@
+package raytracer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Largest image ParseHDR reads, in pixels (8192×4096), so a corrupt or
+// hostile header can't make it allocate without bound.
+const maxHDRPixels = 1 << 25
+
+// ParseHDRFile reads the named Radiance .hdr (RGBE) image.
+func ParseHDRFile(filename string) (*Canvas, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseHDR(f)
+}
+
+// ParseHDR reads a Radiance RGBE image, with flat or run-length encoded
+// scanlines, into a Canvas of unclamped colors. Only the usual top to
+// bottom, left to right orientation ("-Y height +X width") is supported,
+// for images of up to maxHDRPixels pixels.
+func ParseHDR(r io.Reader) (*Canvas, error) {
+	br := bufio.NewReader(r)
+	width, height, err := readHDRHeader(br)
+	if err != nil {
+		return nil, fmt.Errorf("hdr: %v", err)
+	}
+	c := MakeCanvas(width, height)
+	scanline := make([]byte, 4*width)
+	for y := 0; y < height; y++ {
+		if err := readHDRScanline(br, scanline); err != nil {
+			return nil, fmt.Errorf("hdr scanline %d: %v", y, err)
+		}
+		for x := 0; x < width; x++ {
+			c.Set(x, y, rgbeColor(scanline[4*x:4*x+4]))
+		}
+	}
+	return c, nil
+}
+
+// Reads the header lines and resolution line, returning the image size.
+func readHDRHeader(br *bufio.Reader) (width, height int, err error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return 0, 0, err
+	}
+	if !strings.HasPrefix(line, "#?") {
+		return 0, 0, errors.New("missing #? signature")
+	}
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return 0, 0, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return 0, 0, fmt.Errorf("unsupported %s", line)
+		}
+	}
+	line, err = br.ReadString('\n')
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(line, "-Y %d +X %d", &height, &width); err != nil {
+		return 0, 0, fmt.Errorf("unsupported resolution line %q", strings.TrimSpace(line))
+	}
+	if width < 1 || height < 1 {
+		return 0, 0, fmt.Errorf("invalid size %dx%d", width, height)
+	}
+	if width > maxHDRPixels || height > maxHDRPixels/width {
+		return 0, 0, fmt.Errorf("size %dx%d is over the limit of %d pixels", width, height, maxHDRPixels)
+	}
+	return width, height, nil
+}
+
+// Reads one scanline of RGBE pixels into buf, which holds 4 bytes for
+// each pixel.
+func readHDRScanline(br *bufio.Reader, buf []byte) error {
+	width := len(buf) / 4
+	start, err := br.Peek(4)
+	if err != nil {
+		return err
+	}
+	// Run-length encoded scanlines start 2, 2 and the width; anything
+	// else is flat.
+	if width < 8 || width > 0x7fff || start[0] != 2 || start[1] != 2 || start[2]&0x80 != 0 {
+		_, err := io.ReadFull(br, buf)
+		return err
+	}
+	if int(start[2])<<8|int(start[3]) != width {
+		return errors.New("encoded width doesn't match image width")
+	}
+	br.Discard(4)
+	// Each channel is encoded separately, as runs of a repeated byte or
+	// of literal bytes.
+	for ch := 0; ch < 4; ch++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return err
+			}
+			if count > 128 {
+				n := int(count) - 128
+				if x+n > width {
+					return errors.New("run overflows scanline")
+				}
+				b, err := br.ReadByte()
+				if err != nil {
+					return err
+				}
+				for ; n > 0; n-- {
+					buf[4*x+ch] = b
+					x++
+				}
+				continue
+			}
+			n := int(count)
+			if n == 0 || x+n > width {
+				return errors.New("bad literal run length")
+			}
+			for ; n > 0; n-- {
+				b, err := br.ReadByte()
+				if err != nil {
+					return err
+				}
+				buf[4*x+ch] = b
+				x++
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the color of an RGBE pixel: three mantissas sharing an
+// exponent.
+func rgbeColor(p []byte) Color {
+	if p[3] == 0 {
+		return Black()
+	}
+	f := math.Ldexp(1, int(p[3])-(128+8))
+	return Color{float64(p[0]) * f, float64(p[1]) * f, float64(p[2]) * f}
+}
@@ -0,0 +1,87 @@
+package raytracer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func hdrData(header string, pixels ...byte) *bytes.Reader {
+	return bytes.NewReader(append([]byte(header), pixels...))
+}
+
+func TestParseHDR(t *testing.T) {
+	tests := []struct {
+		name string
+		data *bytes.Reader
+		want [][]Color
+	}{
+		{
+			name: "flat",
+			data: hdrData("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 2 +X 1\n",
+				128, 64, 0, 129,
+				0, 0, 0, 0),
+			want: [][]Color{{{1, 0.5, 0}}, {Black()}},
+		},
+		{
+			name: "run-length encoded",
+			data: hdrData("#?RGBE\n# comment\n\n-Y 1 +X 8\n",
+				2, 2, 0, 8,
+				// Red: a run of 8 128s.
+				128+8, 128,
+				// Green: 8 literal values.
+				8, 0, 16, 32, 48, 64, 80, 96, 112,
+				// Blue: a run of 8 zeros.
+				128+8, 0,
+				// Exponent: a run of 4 then a literal 4.
+				128+4, 129, 4, 129, 129, 129, 129),
+			want: [][]Color{{
+				{1, 0, 0}, {1, 0.125, 0}, {1, 0.25, 0}, {1, 0.375, 0},
+				{1, 0.5, 0}, {1, 0.625, 0}, {1, 0.75, 0}, {1, 0.875, 0},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := ParseHDR(test.data)
+			if err != nil {
+				t.Fatalf("got error %v; want nil", err)
+			}
+			if c.Height != len(test.want) || c.Width != len(test.want[0]) {
+				t.Fatalf("size %dx%d; want %dx%d", c.Width, c.Height, len(test.want[0]), len(test.want))
+			}
+			for y, row := range test.want {
+				for x, want := range row {
+					if got := c.Get(x, y); !approxEq(got, want) {
+						t.Errorf("pixel (%d, %d): %s", x, y, approxError(got, want))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseHDRErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *bytes.Reader
+		wantErr string
+	}{
+		{"no signature", hdrData("RADIANCE\n\n-Y 1 +X 1\n", 0, 0, 0, 0), "signature"},
+		{"other format", hdrData("#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n", 0, 0, 0, 0), "unsupported"},
+		{"flipped", hdrData("#?RADIANCE\n\n+Y 1 +X 1\n", 0, 0, 0, 0), "resolution"},
+		{"truncated", hdrData("#?RADIANCE\n\n-Y 2 +X 1\n", 0, 0, 0, 0), "scanline 1"},
+		{"empty", hdrData("#?RADIANCE\n\n-Y 0 +X 4\n"), "invalid size"},
+		{"too big", hdrData("#?RADIANCE\n\n-Y 100000 +X 100000\n", 0, 0, 0, 0), "over the limit"},
+		{"overflowing size", hdrData("#?RADIANCE\n\n-Y 9223372036854775807 +X 2\n", 0, 0, 0, 0), "over the limit"},
+		{"overlong run", hdrData("#?RADIANCE\n\n-Y 1 +X 8\n", 2, 2, 0, 8, 128+9, 0), "overflows"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseHDR(test.data)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("got error %v; want one containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -7,49 +7,145 @@ import (
 
 // The raw intersection returned by intersect().
 type MaterialIntersection struct {
-  ray Ray
-  t float64
-  normalV Vector
-  material *Material
+	ray Ray
+	t   float64
+	// Where the hit lies on the surface, for shapes that parameterize
+	// it (barycentric coordinates for triangles); else zero.
+	u, v     float64
+	normalV  Vector
+	material *Material
 }
 
 func NewMaterialIntersection(r Ray, t float64, normalV Vector, m *Material) MaterialIntersection {
-	return MaterialIntersection{r, t, normalV, m}
+	return MaterialIntersection{r, t, 0, 0, normalV, m}
 }
 
+func NewMaterialIntersectionWithUV(r Ray, t, u, v float64, normalV Vector, m *Material) MaterialIntersection {
+	return MaterialIntersection{r, t, u, v, normalV, m}
+}
+
+// Computes Intersections for a list of raw intersections sorted by t.
+// Refractive indices on either side of each intersection account for
+// every object the ray is inside at that point.
 func Intersections(mxs []MaterialIntersection) []Intersection {
-  xs := []Intersection{}
-  for _, mx := range mxs {
-    xs = append(xs, NewIntersection(mx))
-  }
-  return xs
+	xs := []Intersection{}
+	// Materials of the objects the ray is inside, innermost last.
+	containers := []*Material{}
+	for _, mx := range mxs {
+		x := NewIntersection(mx)
+		x.n1 = innermostRefractiveIndex(containers)
+		containers = toggleContainer(containers, mx.material)
+		x.n2 = innermostRefractiveIndex(containers)
+		xs = append(xs, x)
+	}
+	return xs
 }
 
+func innermostRefractiveIndex(containers []*Material) float64 {
+	if len(containers) == 0 {
+		return 1.0
+	}
+	return containers[len(containers)-1].RefractiveIndex
+}
+
+// Removes m from containers if present, else appends it.
+func toggleContainer(containers []*Material, m *Material) []*Material {
+	for i, c := range containers {
+		if c == m {
+			return append(containers[:i], containers[i+1:]...)
+		}
+	}
+	return append(containers, m)
+}
+
+// Distance along the normal that secondary rays start from, so that
+// they don't immediately re-hit the surface they leave (shadow acne).
+const epsilon = 0.00001
+
 // An intersection with additional computed values.
 type Intersection struct {
-	t float64
-	point   Point
-	eyeV    Vector
-	normalV Vector
-  inside bool
-  material *Material
+	t     float64
+	point Point
+	// point nudged along normalV, for casting rays away from the surface.
+	overPoint Point
+	// point nudged against normalV, for casting refracted rays.
+	underPoint Point
+	eyeV       Vector
+	normalV    Vector
+	// Direction of the mirror reflection of the incoming ray.
+	reflectV Vector
+	inside   bool
+	material *Material
+	// Refractive indices on the incoming (n1) and outgoing (n2) sides.
+	n1, n2 float64
+	// When the ray was cast, for casting further rays.
+	time float64
 }
 
 func NewIntersection(mi MaterialIntersection) Intersection {
-  point := mi.ray.position(mi.t)
-  eyeV := mi.ray.dir.Negate()
-  normalV := mi.normalV
-  inside := false
-  if normalV.Dot(eyeV) < 0.0 {
-    inside = true
-    normalV = normalV.Negate()
-  }
-
-	return Intersection{mi.t, point, eyeV, normalV, inside, mi.material}
+	point := mi.ray.position(mi.t)
+	eyeV := mi.ray.dir.Negate()
+	normalV := mi.normalV
+	inside := false
+	if normalV.Dot(eyeV) < 0.0 {
+		inside = true
+		normalV = normalV.Negate()
+	}
+
+	overPoint := point.PlusV(normalV.Scale(epsilon))
+	underPoint := point.MinusV(normalV.Scale(epsilon))
+	reflectV := mi.ray.dir.Reflect(normalV)
+
+	// Without the other intersections along the ray, assume the object
+	// is surrounded by vacuum.
+	n1, n2 := 1.0, mi.material.RefractiveIndex
+	if inside {
+		n1, n2 = n2, n1
+	}
+	return Intersection{mi.t, point, overPoint, underPoint, eyeV, normalV, reflectV, inside, mi.material, n1, n2, mi.ray.time}
 }
 
+// Intersections compare equal if their t values are within this distance.
+const tTolerance = 0.0001
+
 func IntersectionTComparer() cmp.Option {
-	return cmp.Comparer(func(x, y Intersection) bool { return x.t == y.t })
+	return cmp.Comparer(func(x, y Intersection) bool { return math.Abs(x.t-y.t) < tTolerance })
+}
+
+func MaterialIntersectionTComparer() cmp.Option {
+	return cmp.Comparer(func(x, y MaterialIntersection) bool { return math.Abs(x.t-y.t) < tTolerance })
+}
+
+// Returns the Schlick approximation of the fraction of light reflected
+// at x, as opposed to refracted.
+func (x *Intersection) schlick() float64 {
+	cos := x.eyeV.Dot(x.normalV)
+	if x.n1 > x.n2 {
+		n := x.n1 / x.n2
+		sin2T := n * n * (1.0 - cos*cos)
+		if sin2T > 1.0 {
+			// Total internal reflection.
+			return 1.0
+		}
+		cos = math.Sqrt(1.0 - sin2T)
+	}
+	r0 := (x.n1 - x.n2) / (x.n1 + x.n2)
+	r0 = r0 * r0
+	return r0 + (1-r0)*math.Pow(1-cos, 5)
+}
+
+// Returns the direction, by Snell's law, that light reaching the eye
+// along eyeV was refracted from at x, and false if there is none because
+// of total internal reflection.
+func (x *Intersection) refractV() (Vector, bool) {
+	nRatio := x.n1 / x.n2
+	cosI := x.eyeV.Dot(x.normalV)
+	sin2T := nRatio * nRatio * (1 - cosI*cosI)
+	if sin2T > 1 {
+		return Vector{}, false
+	}
+	cosT := math.Sqrt(1.0 - sin2T)
+	return x.normalV.Scale(nRatio*cosI - cosT).Minus(x.eyeV.Scale(nRatio)), true
 }
 
 // Returns the intersection with the lowest non-negative t, or nil.
@@ -1,10 +1,242 @@
 package raytracer
 
-type Light struct {
-	position  Point
-	intensity Color
+import "math"
+
+// A Light illuminates points in the world.
+type Light interface {
+	// Returns the unit vector from p toward the light, the distance to
+	// the light (+Inf if infinitely far), and the intensity of the light
+	// arriving at p, ignoring anything in the way.
+	Illuminate(p Point) (direction Vector, distance float64, intensity Color)
+}
+
+// Attenuation dims light with distance d by 1/(Constant + Linear*d +
+// Quadratic*d²). The zero value leaves light undimmed.
+type Attenuation struct {
+	Constant, Linear, Quadratic float64
+}
+
+func (a Attenuation) factor(d float64) float64 {
+	denom := a.Constant + a.Linear*d + a.Quadratic*d*d
+	if denom == 0 {
+		return 1
+	}
+	return 1 / denom
+}
+
+// PointLight shines equally in all directions from Position.
+type PointLight struct {
+	Position    Point
+	Intensity   Color
+	Attenuation Attenuation
+}
+
+func NewPointLight(position Point, intensity Color) *PointLight {
+	return &PointLight{Position: position, Intensity: intensity}
+}
+
+func (l *PointLight) Illuminate(p Point) (Vector, float64, Color) {
+	v := l.Position.Minus(p)
+	d := v.Magnitude()
+	return v.Scale(1 / d), d, l.Intensity.scale(l.Attenuation.factor(d))
+}
+
+// DirectionalLight shines from infinitely far away, like the sun, along
+// Direction everywhere.
+type DirectionalLight struct {
+	// Direction the light travels in.
+	Direction Vector
+	Intensity Color
+}
+
+func NewDirectionalLight(direction Vector, intensity Color) *DirectionalLight {
+	return &DirectionalLight{direction, intensity}
+}
+
+func (l *DirectionalLight) Illuminate(p Point) (Vector, float64, Color) {
+	return l.Direction.Norm().Negate(), math.Inf(1), l.Intensity
+}
+
+// SpotLight shines from Position in a cone around Direction. Within
+// InnerAngle of Direction it is at full intensity; beyond OuterAngle it
+// gives no light; between, its intensity falls smoothly, faster for
+// greater Falloff.
+type SpotLight struct {
+	Position  Point
+	Direction Vector
+	Intensity Color
+	// Half-angles of the cones, in radians.
+	InnerAngle, OuterAngle float64
+	// Exponent applied to the fall in intensity between the cones; 0
+	// means 1.
+	Falloff     float64
+	Attenuation Attenuation
+}
+
+func NewSpotLight(position Point, direction Vector, intensity Color, innerAngle, outerAngle float64) *SpotLight {
+	return &SpotLight{
+		Position:   position,
+		Direction:  direction,
+		Intensity:  intensity,
+		InnerAngle: innerAngle,
+		OuterAngle: outerAngle,
+	}
+}
+
+func (l *SpotLight) Illuminate(p Point) (Vector, float64, Color) {
+	v := l.Position.Minus(p)
+	d := v.Magnitude()
+	toP := v.Scale(1 / d)
+	return toP, d, l.Intensity.scale(l.cone(toP.Negate()) * l.Attenuation.factor(d))
+}
+
+// Returns the fraction of the light's intensity shone along unit vector v.
+func (l *SpotLight) cone(v Vector) float64 {
+	cos := v.Dot(l.Direction.Norm())
+	cosInner, cosOuter := math.Cos(l.InnerAngle), math.Cos(l.OuterAngle)
+	switch {
+	case cos >= cosInner:
+		return 1
+	case cos <= cosOuter:
+		return 0
+	}
+	// Smoothstep between the cones.
+	t := (cos - cosOuter) / (cosInner - cosOuter)
+	f := t * t * (3 - 2*t)
+	if l.Falloff > 0 {
+		f = math.Pow(f, l.Falloff)
+	}
+	return f
+}
+
+// An AreaLight is a Light with extent. Light reaches a point from many
+// places on it, so shadows have soft edges.
+type AreaLight interface {
+	Light
+	// Returns the places on the light sampled when lighting p. Jittered
+	// samples are random, but always the same for the same p.
+	SamplePoints(p Point) []Point
+}
+
+// RectLight is a parallelogram of light with one corner at Corner and
+// sides UVec and VVec, sampled on a USteps×VSteps grid. Steps below 1
+// count as 1.
+type RectLight struct {
+	Corner         Point
+	UVec, VVec     Vector
+	USteps, VSteps int
+	Intensity      Color
+	// Where samples fall within each cell of the grid.
+	Sampling SamplingPattern
+	// Seeds jittered samples.
+	Seed int64
+}
+
+func NewRectLight(corner Point, uvec Vector, usteps int, vvec Vector, vsteps int, intensity Color) *RectLight {
+	return &RectLight{
+		Corner:    corner,
+		UVec:      uvec,
+		VVec:      vvec,
+		USteps:    atLeastOne(usteps),
+		VSteps:    atLeastOne(vsteps),
+		Intensity: intensity,
+	}
+}
+
+// Illuminates p as if all the light came from the middle of the light.
+func (l *RectLight) Illuminate(p Point) (Vector, float64, Color) {
+	return illuminateFrom(l.Corner.PlusV(l.UVec.Scale(0.5)).PlusV(l.VVec.Scale(0.5)), p, l.Intensity)
+}
+
+func (l *RectLight) SamplePoints(p Point) []Point {
+	rnd := newPointRand(l.Seed, p)
+	usteps, vsteps := atLeastOne(l.USteps), atLeastOne(l.VSteps)
+	points := make([]Point, 0, usteps*vsteps)
+	for v := 0; v < vsteps; v++ {
+		for u := 0; u < usteps; u++ {
+			ou, ov := 0.5, 0.5
+			if l.Sampling == Jittered {
+				ou, ov = rnd.next(), rnd.next()
+			}
+			uOffset := l.UVec.Scale((float64(u) + ou) / float64(usteps))
+			vOffset := l.VVec.Scale((float64(v) + ov) / float64(vsteps))
+			points = append(points, l.Corner.PlusV(uOffset).PlusV(vOffset))
+		}
+	}
+	return points
+}
+
+// DiskLight is a disk of light of the given Radius around Center, facing
+// along Normal, sampled on a Steps×Steps grid mapped onto the disk.
+// Steps below 1 count as 1.
+type DiskLight struct {
+	Center    Point
+	Normal    Vector
+	Radius    float64
+	Steps     int
+	Intensity Color
+	// Where samples fall within each cell of the grid.
+	Sampling SamplingPattern
+	// Seeds jittered samples.
+	Seed int64
+}
+
+func NewDiskLight(center Point, normal Vector, radius float64, steps int, intensity Color) *DiskLight {
+	return &DiskLight{
+		Center:    center,
+		Normal:    normal,
+		Radius:    radius,
+		Steps:     atLeastOne(steps),
+		Intensity: intensity,
+	}
+}
+
+// Illuminates p as if all the light came from the middle of the light.
+func (l *DiskLight) Illuminate(p Point) (Vector, float64, Color) {
+	return illuminateFrom(l.Center, p, l.Intensity)
+}
+
+func (l *DiskLight) SamplePoints(p Point) []Point {
+	rnd := newPointRand(l.Seed, p)
+	u, v := perpendiculars(l.Normal.Norm())
+	steps := atLeastOne(l.Steps)
+	n := float64(steps)
+	points := make([]Point, 0, steps*steps)
+	for j := 0; j < steps; j++ {
+		for i := 0; i < steps; i++ {
+			oi, oj := 0.5, 0.5
+			if l.Sampling == Jittered {
+				oi, oj = rnd.next(), rnd.next()
+			}
+			x, y := sampleDisk((float64(i)+oi)/n, (float64(j)+oj)/n)
+			points = append(points, l.Center.PlusV(u.Scale(x*l.Radius)).PlusV(v.Scale(y*l.Radius)))
+		}
+	}
+	return points
+}
+
+// Returns the direction, distance and intensity of light from an
+// unattenuated source at from.
+func illuminateFrom(from, p Point, intensity Color) (Vector, float64, Color) {
+	v := from.Minus(p)
+	d := v.Magnitude()
+	return v.Scale(1 / d), d, intensity
+}
+
+// Returns unit vectors perpendicular to unit vector n and each other.
+func perpendiculars(n Vector) (Vector, Vector) {
+	a := Vector{1, 0, 0}
+	if math.Abs(n.X) > 0.9 {
+		a = Vector{0, 1, 0}
+	}
+	u := n.Cross(a).Norm()
+	return u, n.Cross(u)
 }
 
-func NewPointLight(position Point, intensity Color) Light {
-	return Light{position, intensity}
+// Returns n, or 1 if n is less, for counts of samples.
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
 }
@@ -0,0 +1,208 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLightIlluminate(t *testing.T) {
+	// Spotlight cones of 30° and 60° pointing down from (0, 10, 0).
+	spot := NewSpotLight(Point{0, 10, 0}, Vector{0, -1, 0}, White(), math.Pi/6, math.Pi/3)
+	sharpSpot := *spot
+	sharpSpot.Falloff = 2
+	// Halfway between the cones, by cosine.
+	mid := math.Acos((math.Cos(math.Pi/6) + math.Cos(math.Pi/3)) / 2)
+	midP := Point{10 * math.Tan(mid), 0, 0}
+	midDir := Vector{-math.Sin(mid), math.Cos(mid), 0}
+	attenuated := NewPointLight(Point{0, 0, -10}, White())
+	attenuated.Attenuation = Attenuation{1, 0.1, 0.01}
+	tests := []struct {
+		name          string
+		light         Light
+		p             Point
+		wantDir       Vector
+		wantDistance  float64
+		wantIntensity Color
+	}{
+		{
+			name:          "point",
+			light:         NewPointLight(Point{0, 0, -10}, Color{0.5, 0.5, 0.5}),
+			p:             Point{0, 0, 0},
+			wantDir:       Vector{0, 0, -1},
+			wantDistance:  10,
+			wantIntensity: Color{0.5, 0.5, 0.5},
+		},
+		{
+			name:          "attenuated point",
+			light:         attenuated,
+			p:             Point{0, 0, 0},
+			wantDir:       Vector{0, 0, -1},
+			wantDistance:  10,
+			wantIntensity: Color{1.0 / 3, 1.0 / 3, 1.0 / 3},
+		},
+		{
+			name:          "directional",
+			light:         NewDirectionalLight(Vector{0, -2, 0}, White()),
+			p:             Point{5, 0, 3},
+			wantDir:       Vector{0, 1, 0},
+			wantDistance:  math.Inf(1),
+			wantIntensity: White(),
+		},
+		{
+			name:          "spot inside inner cone",
+			light:         spot,
+			p:             Point{1, 0, 0},
+			wantDir:       Vector{-1, 10, 0}.Norm(),
+			wantDistance:  math.Sqrt(101),
+			wantIntensity: White(),
+		},
+		{
+			name:          "spot between cones",
+			light:         spot,
+			p:             midP,
+			wantDir:       midDir,
+			wantDistance:  10 / math.Cos(mid),
+			wantIntensity: Color{0.5, 0.5, 0.5},
+		},
+		{
+			name:          "spot between cones with falloff",
+			light:         &sharpSpot,
+			p:             midP,
+			wantDir:       midDir,
+			wantDistance:  10 / math.Cos(mid),
+			wantIntensity: Color{0.25, 0.25, 0.25},
+		},
+		{
+			name:          "spot outside outer cone",
+			light:         spot,
+			p:             Point{20, 0, 0},
+			wantDir:       Vector{-2, 1, 0}.Norm(),
+			wantDistance:  math.Sqrt(500),
+			wantIntensity: Black(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, distance, intensity := test.light.Illuminate(test.p)
+			if !approxEq(dir, test.wantDir) {
+				t.Errorf("direction: %s", approxError(dir, test.wantDir))
+			}
+			if !approxEq(distance, test.wantDistance) {
+				t.Errorf("distance: %s", approxError(distance, test.wantDistance))
+			}
+			if !approxEq(intensity, test.wantIntensity) {
+				t.Errorf("intensity: %s", approxError(intensity, test.wantIntensity))
+			}
+		})
+	}
+}
+
+func TestWorldIsShadowedDirectionalLight(t *testing.T) {
+	w := NewEmptyWorld()
+	w.AddLight(NewDirectionalLight(Vector{0, -1, 0}, White()))
+	w.AddShape(NewSphere(MakeTranslation(0, 100, 0)))
+	if !w.IsShadowed(Point{0, 0, 0}, w.Lights()[0], 0) {
+		t.Error("point under a distant sphere not shadowed")
+	}
+	if w.IsShadowed(Point{2, 0, 0}, w.Lights()[0], 0) {
+		t.Error("point beside a distant sphere shadowed")
+	}
+}
+
+func TestRectLightSamplePoints(t *testing.T) {
+	light := NewRectLight(Point{0, 0, 0}, Vector{2, 0, 0}, 4, Vector{0, 0, 1}, 2, White())
+	got := light.SamplePoints(Point{0, 0, -5})
+	want := []Point{
+		{0.25, 0, 0.25}, {0.75, 0, 0.25}, {1.25, 0, 0.25}, {1.75, 0, 0.25},
+		{0.25, 0, 0.75}, {0.75, 0, 0.75}, {1.25, 0, 0.75}, {1.75, 0, 0.75},
+	}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+
+	light.Sampling = Jittered
+	p := Point{1, 2, 3}
+	jittered := light.SamplePoints(p)
+	for i, jp := range jittered {
+		// Each sample stays within its own cell.
+		if math.Abs(jp.X-want[i].X) > 0.25 || jp.Y != 0 || math.Abs(jp.Z-want[i].Z) > 0.25 {
+			t.Errorf("sample %d at %v; want within the cell around %v", i, jp, want[i])
+		}
+	}
+	if again := light.SamplePoints(p); !approxEq(again, jittered) {
+		t.Errorf("samples for the same point differ: %s", approxError(again, jittered))
+	}
+	light.Seed = 1
+	if other := light.SamplePoints(p); approxEq(other, jittered) {
+		t.Error("samples with a different seed are the same")
+	}
+}
+
+func TestDiskLightSamplePoints(t *testing.T) {
+	light := NewDiskLight(Point{1, 5, 0}, Vector{0, -1, 0}, 2, 4, White())
+	light.Sampling = Jittered
+	points := light.SamplePoints(Point{0, 0, 0})
+	if len(points) != 16 {
+		t.Fatalf("got %d samples; want 16", len(points))
+	}
+	for _, p := range points {
+		v := p.Minus(light.Center)
+		if !approxEq(v.Y, 0.0) || v.Magnitude() > light.Radius {
+			t.Errorf("sample %v not on the disk", p)
+		}
+	}
+}
+
+func TestAreaLightZeroSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		light AreaLight
+		want  Point
+	}{
+		{"rect", NewRectLight(Point{0, 5, 0}, Vector{2, 0, 0}, 0, Vector{0, 0, 2}, 0, White()), Point{1, 5, 1}},
+		{"rect literal", &RectLight{Corner: Point{0, 5, 0}, UVec: Vector{2, 0, 0}, VVec: Vector{0, 0, 2}, Intensity: White()}, Point{1, 5, 1}},
+		{"disk", NewDiskLight(Point{1, 5, 0}, Vector{0, -1, 0}, 2, 0, White()), Point{1, 5, 0}},
+		{"disk literal", &DiskLight{Center: Point{1, 5, 0}, Normal: Vector{0, -1, 0}, Radius: 2, Intensity: White()}, Point{1, 5, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A single sample at the middle of the light.
+			got, want := test.light.SamplePoints(Point{0, 0, 0}), []Point{test.want}
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+			w := NewEmptyWorld()
+			w.AddShape(NewSphere(MakeIdentity()))
+			w.AddLight(test.light)
+			r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+			c := w.ColorAt(r)
+			if math.IsNaN(c.R) || math.IsNaN(c.G) || math.IsNaN(c.B) {
+				t.Errorf("ColorAt() = %v; want no NaN", c)
+			}
+		})
+	}
+}
+
+func TestWorldLightVisibility(t *testing.T) {
+	w := NewDefaultWorld()
+	light := NewRectLight(Point{-0.5, -0.5, -5}, Vector{1, 0, 0}, 2, Vector{0, 1, 0}, 2, White())
+	tests := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{0, 0, 2}, 0},
+		{Point{1, -1, 2}, 0.25},
+		{Point{1.5, 0, 2}, 0.5},
+		{Point{1.25, 1.25, 3}, 0.75},
+		{Point{0, 0, -2}, 1},
+	}
+	for _, test := range tests {
+		if got := w.LightVisibility(test.p, light, 0); !approxEq(got, test.want) {
+			t.Errorf("%v: %s", test.p, approxError(got, test.want))
+		}
+	}
+	// Point lights are all or nothing.
+	if got := w.LightVisibility(Point{0, 0, 2}, NewPointLight(Point{0, 0, -5}, White()), 0); got != 0 {
+		t.Errorf("point light visibility = %v; want 0", got)
+	}
+}
@@ -8,30 +8,54 @@ type Material struct {
 	Diffuse   float64
 	Specular  float64
 	Shininess float64
+	// Fraction of light reflected as a mirror, 0 (matte) to 1.
+	Reflective float64
+	// Fraction of light passed through, 0 (opaque) to 1.
+	Transparency float64
+	// Index of refraction: 1.0 for vacuum, 1.333 water, 1.5 glass.
+	RefractiveIndex float64
+	// Light given off by the surface itself, which makes it a light source
+	// for NewPathTracer.
+	Emissive Color
 }
 
 func NewMaterial() *Material {
-	return &Material{White(), 0.1, 0.9, 0.9, 200.0}
+	return &Material{White(), 0.1, 0.9, 0.9, 200.0, 0.0, 0.0, 1.0, Black()}
 }
 
-func Lighting(m *Material, light Light, position Point, eyeV Vector, normalV Vector) Color {
-	effectiveColor := m.Color.times(light.intensity)
-	lightV := light.position.Minus(position).Norm()
+// Returns the Phong-lit color of material m at position. visibility is
+// the fraction of the light not in shadow, which scales the diffuse and
+// specular terms; those are averaged over the light's samples for an
+// AreaLight.
+func Lighting(m *Material, light Light, position Point, eyeV Vector, normalV Vector, visibility float64) Color {
+	lightV, _, intensity := light.Illuminate(position)
+	effectiveColor := m.Color.times(intensity)
 
 	ambient := effectiveColor.scale(m.Ambient)
-	diffuse := Black()
-	specular := Black()
+	if visibility <= 0 {
+		return ambient
+	}
+	lightVs := []Vector{lightV}
+	if area, ok := light.(AreaLight); ok {
+		lightVs = lightVs[:0]
+		for _, p := range area.SamplePoints(position) {
+			lightVs = append(lightVs, p.Minus(position).Norm())
+		}
+	}
 
-	lightDotNormal := lightV.Dot(normalV)
-	if lightDotNormal > 0 {
-		diffuse = effectiveColor.scale(m.Diffuse * lightDotNormal)
+	sum := Black()
+	for _, lightV := range lightVs {
+		lightDotNormal := lightV.Dot(normalV)
+		if lightDotNormal <= 0 {
+			continue
+		}
+		sum = sum.plus(effectiveColor.scale(m.Diffuse * lightDotNormal))
 		reflectV := lightV.Negate().Reflect(normalV)
 		reflectDotEye := reflectV.Dot(eyeV)
 		if reflectDotEye > 0 {
 			factor := math.Pow(reflectDotEye, m.Shininess)
-			specular = light.intensity.scale(m.Specular * factor)
+			sum = sum.plus(intensity.scale(m.Specular * factor))
 		}
 	}
-
-	return ambient.plus(diffuse).plus(specular)
+	return ambient.plus(sum.scale(visibility / float64(len(lightVs))))
 }
@@ -4,46 +4,84 @@ import "testing"
 
 func TestLighting(t *testing.T) {
 	tests := []struct {
-		name    string
-		eyev    Vector
-		normalv Vector
-		light   Light
-		want    Color
+		name       string
+		eyev       Vector
+		normalv    Vector
+		light      Light
+		visibility float64
+		want       Color
 	}{
 		{
-			name:    "Lighting with the eye between the light and the surface",
-			eyev:    Vector{0, 0, -1},
-			normalv: Vector{0, 0, -1},
-			light:   NewPointLight(Point{0, 0, -10}, White()),
-			want:    Color{1.9, 1.9, 1.9},
+			name:       "Lighting with the eye between the light and the surface",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 0, -10}, White()),
+			visibility: 1,
+			want:       Color{1.9, 1.9, 1.9},
 		},
 		{
-			name:    "Lighting with the eye between the light and the surface, eye offset 45",
-			eyev:    Vector{0, 1, -1}.Norm(),
-			normalv: Vector{0, 0, -1},
-			light:   NewPointLight(Point{0, 0, -10}, White()),
-			want:    Color{1.0, 1.0, 1.0},
+			name:       "Lighting with the eye between the light and the surface, eye offset 45",
+			eyev:       Vector{0, 1, -1}.Norm(),
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 0, -10}, White()),
+			visibility: 1,
+			want:       Color{1.0, 1.0, 1.0},
 		},
 		{
-			name:    "Lighting with the eye opposite surface, light offset 45",
-			eyev:    Vector{0, 0, -1},
-			normalv: Vector{0, 0, -1},
-			light:   NewPointLight(Point{0, 10, -10}, White()),
-			want:    Color{0.7364, 0.7364, 0.7364},
+			name:       "Lighting with the eye opposite surface, light offset 45",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 10, -10}, White()),
+			visibility: 1,
+			want:       Color{0.7364, 0.7364, 0.7364},
 		},
 		{
-			name:    "Lighting with the eye in the path of the reflection vector",
-			eyev:    Vector{0, -1, -1}.Norm(),
-			normalv: Vector{0, 0, -1},
-			light:   NewPointLight(Point{0, 10, -10}, White()),
-			want:    Color{1.6364, 1.6364, 1.6364},
+			name:       "Lighting with the eye in the path of the reflection vector",
+			eyev:       Vector{0, -1, -1}.Norm(),
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 10, -10}, White()),
+			visibility: 1,
+			want:       Color{1.6364, 1.6364, 1.6364},
 		},
 		{
-			name:    "Lighting with the light behind the surface",
-			eyev:    Vector{0, 0, -1},
-			normalv: Vector{0, 0, -1},
-			light:   NewPointLight(Point{0, 0, 10}, White()),
-			want:    Color{0.1, 0.1, 0.1},
+			name:       "Lighting with the light behind the surface",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 0, 10}, White()),
+			visibility: 1,
+			want:       Color{0.1, 0.1, 0.1},
+		},
+		{
+			name:       "Lighting with the surface in shadow",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 0, -10}, White()),
+			visibility: 0,
+			want:       Color{0.1, 0.1, 0.1},
+		},
+		{
+			name:       "Lighting with the light half visible",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewPointLight(Point{0, 0, -10}, White()),
+			visibility: 0.5,
+			want:       Color{1.0, 1.0, 1.0},
+		},
+		{
+			name:       "Lighting with a directional light head on",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewDirectionalLight(Vector{0, 0, 1}, White()),
+			visibility: 1,
+			want:       Color{1.9, 1.9, 1.9},
+		},
+		{
+			name:       "Lighting with the surface outside a spotlight's cone",
+			eyev:       Vector{0, 0, -1},
+			normalv:    Vector{0, 0, -1},
+			light:      NewSpotLight(Point{0, 0, -10}, Vector{0, 1, 0}, White(), 0.1, 0.2),
+			visibility: 1,
+			want:       Color{0, 0, 0},
 		},
 	}
 
@@ -52,10 +90,35 @@ func TestLighting(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, want := Lighting(material, test.light, position, test.eyev, test.normalv), test.want
+			got, want := Lighting(material, test.light, position, test.eyev, test.normalv, test.visibility), test.want
 			if !approxEq(got, want) {
 				t.Error(approxError(got, want))
 			}
 		})
 	}
 }
+
+func TestLightingAreaLight(t *testing.T) {
+	light := NewRectLight(Point{-0.5, -0.5, -5}, Vector{1, 0, 0}, 2, Vector{0, 1, 0}, 2, White())
+	m := NewMaterial()
+	m.Ambient = 0.1
+	m.Diffuse = 0.9
+	m.Specular = 0
+	eye := Point{0, 0, -5}
+	tests := []struct {
+		p    Point
+		want Color
+	}{
+		{Point{0, 0, -1}, Color{0.9965, 0.9965, 0.9965}},
+		{Point{0, 0.7071, -0.7071}, Color{0.6232, 0.6232, 0.6232}},
+	}
+	for _, test := range tests {
+		// Points on a unit sphere at the origin, so the normal is p.
+		normalV := test.p.Minus(Point{0, 0, 0})
+		eyeV := eye.Minus(test.p).Norm()
+		got := Lighting(m, light, test.p, eyeV, normalV, 1)
+		if !approxEq(got, test.want) {
+			t.Errorf("%v: %s", test.p, approxError(got, test.want))
+		}
+	}
+}
@@ -53,6 +53,37 @@ func MakeIdentityWithSize(size int) *Matrix {
 	return m
 }
 
+func (m *Matrix) isIdentity() bool {
+	if m.numRows != m.numCols {
+		return false
+	}
+	for r := 0; r < m.numRows; r++ {
+		for c := 0; c < m.numCols; c++ {
+			want := 0.0
+			if r == c {
+				want = 1.0
+			}
+			if m.get(r, c) != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Reports whether m and m2 have the same size and exactly the same cells.
+func (m *Matrix) equals(m2 *Matrix) bool {
+	if m.numRows != m2.numRows || m.numCols != m2.numCols {
+		return false
+	}
+	for i := range m.val {
+		if m.val[i] != m2.val[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func MakeTranslation(x, y, z float64) *Matrix {
 	m := MakeIdentity()
 	m.set(0, 3, x)
@@ -270,3 +301,19 @@ func (m *Matrix) cofactor(r, c int) float64 {
 	}
 	return cof
 }
+
+// MakeViewTransform builds the transform that orients the world relative
+// to an eye at from, looking toward to, with up as the approximate
+// upward direction.
+func MakeViewTransform(from, to Point, up Vector) *Matrix {
+	forward := to.Minus(from).Norm()
+	left := forward.Cross(up.Norm())
+	trueUp := left.Cross(forward)
+	orientation := MakeMatrix([][]float64{
+		{left.X, left.Y, left.Z, 0},
+		{trueUp.X, trueUp.Y, trueUp.Z, 0},
+		{-forward.X, -forward.Y, -forward.Z, 0},
+		{0, 0, 0, 1},
+	})
+	return orientation.Times(MakeTranslation(-from.X, -from.Y, -from.Z))
+}
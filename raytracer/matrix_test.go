@@ -735,3 +735,55 @@ func TestMatrixChaining(t *testing.T) {
 		t.Error(approxError(got, want))
 	}
 }
+
+func TestMakeViewTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		from Point
+		to   Point
+		up   Vector
+		want *Matrix
+	}{
+		{
+			name: "default orientation",
+			from: Point{0, 0, 0},
+			to:   Point{0, 0, -1},
+			up:   Vector{0, 1, 0},
+			want: MakeIdentity(),
+		},
+		{
+			name: "looking in positive z direction",
+			from: Point{0, 0, 0},
+			to:   Point{0, 0, 1},
+			up:   Vector{0, 1, 0},
+			want: MakeScaling(-1, 1, -1),
+		},
+		{
+			name: "moves the world",
+			from: Point{0, 0, 8},
+			to:   Point{0, 0, 0},
+			up:   Vector{0, 1, 0},
+			want: MakeTranslation(0, 0, -8),
+		},
+		{
+			name: "arbitrary",
+			from: Point{1, 3, 2},
+			to:   Point{4, -2, 8},
+			up:   Vector{1, 1, 0},
+			want: MakeMatrix([][]float64{
+				{-0.50709, 0.50709, 0.67612, -2.36643},
+				{0.76772, 0.60609, 0.12122, -2.82843},
+				{-0.35857, 0.59761, -0.71714, 0.00000},
+				{0.00000, 0.00000, 0.00000, 1.00000},
+			}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := MakeViewTransform(test.from, test.to, test.up), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package raytracer
+
+import (
+	"math"
+	"sort"
+)
+
+// A Keyframe gives a moving shape's transform at a point in time.
+type Keyframe struct {
+	Time  float64
+	Xform *Matrix
+}
+
+// Number of steps between keyframes at which moving bounds are sampled.
+const motionBoundsSteps = 16
+
+// motion interpolates a shape's transform between keyframes: translation
+// and stretch (scale and shear) linearly, rotation along the shortest arc.
+type motion struct {
+	// Sorted by time.
+	keyframes []Keyframe
+	inverses  []*Matrix
+	parts     []xformParts
+}
+
+func newMotion(keyframes []Keyframe) *motion {
+	if len(keyframes) == 0 {
+		panic("motion needs at least one keyframe")
+	}
+	m := &motion{keyframes: append([]Keyframe{}, keyframes...)}
+	sort.SliceStable(m.keyframes, func(i, j int) bool {
+		return m.keyframes[i].Time < m.keyframes[j].Time
+	})
+	for i, k := range m.keyframes {
+		m.keyframes[i].Xform = k.Xform.Copy()
+		m.inverses = append(m.inverses, k.Xform.inverse())
+		m.parts = append(m.parts, decompose(k.Xform))
+	}
+	return m
+}
+
+// Returns the transform and its inverse at the given time. The shape
+// holds still before the first keyframe and after the last.
+func (m *motion) at(time float64) (xf, ixf *Matrix) {
+	i := sort.Search(len(m.keyframes), func(i int) bool {
+		return m.keyframes[i].Time > time
+	})
+	switch {
+	case i == 0:
+		return m.keyframes[0].Xform, m.inverses[0]
+	case i == len(m.keyframes) || m.keyframes[i-1].Time == time:
+		return m.keyframes[i-1].Xform, m.inverses[i-1]
+	case m.keyframes[i-1].Xform.equals(m.keyframes[i].Xform):
+		// Holding still between the two.
+		return m.keyframes[i-1].Xform, m.inverses[i-1]
+	}
+	t0, t1 := m.keyframes[i-1].Time, m.keyframes[i].Time
+	p := m.parts[i-1].lerp(m.parts[i], (time-t0)/(t1-t0))
+	return p.matrix(), p.inverse()
+}
+
+// Converts object-space bounds into parent space, covering the whole
+// motion. Bounds are sampled between keyframes, and padded slightly for
+// the arcs of rotations between samples.
+func (m *motion) bounds(objectBounds Bounds) Bounds {
+	b := EmptyBounds()
+	for i := range m.keyframes {
+		b = b.union(objectBounds.xform(m.keyframes[i].Xform))
+		if i == 0 {
+			continue
+		}
+		t0, t1 := m.keyframes[i-1].Time, m.keyframes[i].Time
+		for s := 1; s < motionBoundsSteps; s++ {
+			xf, _ := m.at(t0 + (t1-t0)*float64(s)/motionBoundsSteps)
+			b = b.union(objectBounds.xform(xf))
+		}
+	}
+	if !b.isFinite() || len(m.keyframes) == 1 {
+		return b
+	}
+	pad := b.Max.Minus(b.Min).Scale(0.01)
+	return Bounds{b.Min.MinusV(pad), b.Max.PlusV(pad)}
+}
+
+// xformParts is a transform split into a stretch, then rotation, then
+// translation.
+type xformParts struct {
+	// Symmetric 3x3 matrix, holding scale and shear. A reflection makes
+	// it negative.
+	stretch     *Matrix
+	rotation    quaternion
+	translation Vector
+}
+
+// Largest number of iterations polarRotation takes to converge.
+const polarIterations = 32
+
+// Splits m into stretch, rotation and translation, by the polar
+// decomposition of its 3x3 part.
+func decompose(m *Matrix) xformParts {
+	a := MakeMatrixWithSize(3, 3)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			a.set(r, c, m.get(r, c))
+		}
+	}
+	q := a.Copy()
+	if a.determinant() < 0 {
+		// A reflection; find the rotation for -a, and fold the -1 into
+		// the stretch.
+		for i := range q.val {
+			q.val[i] = -q.val[i]
+		}
+	}
+	q = polarRotation(q)
+	stretch := q.transpose().Times(a)
+	// Symmetric up to rounding; make it exactly so.
+	for r := 0; r < 3; r++ {
+		for c := r + 1; c < 3; c++ {
+			v := (stretch.get(r, c) + stretch.get(c, r)) / 2
+			stretch.set(r, c, v)
+			stretch.set(c, r, v)
+		}
+	}
+	var rot [3][3]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			rot[r][c] = q.get(r, c)
+		}
+	}
+	return xformParts{stretch, quaternionFromRotation(rot), Vector{m.get(0, 3), m.get(1, 3), m.get(2, 3)}}
+}
+
+// Returns the rotation nearest to the 3x3 matrix a, which must have a
+// positive determinant, by averaging it with its inverse transpose until
+// that no longer changes it.
+func polarRotation(a *Matrix) *Matrix {
+	q := a.Copy()
+	for i := 0; i < polarIterations; i++ {
+		it := q.inverse().transpose()
+		change := 0.0
+		for j := range q.val {
+			v := (q.val[j] + it.val[j]) / 2
+			change = math.Max(change, math.Abs(v-q.val[j]))
+			q.val[j] = v
+		}
+		if change < 1e-12 {
+			break
+		}
+	}
+	return q
+}
+
+// Returns the parts f of the way from p to p2.
+func (p xformParts) lerp(p2 xformParts, f float64) xformParts {
+	stretch := MakeMatrixWithSize(3, 3)
+	for i := range stretch.val {
+		stretch.val[i] = p.stretch.val[i] + (p2.stretch.val[i]-p.stretch.val[i])*f
+	}
+	return xformParts{
+		stretch,
+		p.rotation.slerp(p2.rotation, f),
+		p.translation.Plus(p2.translation.Minus(p.translation).Scale(f)),
+	}
+}
+
+func (p xformParts) matrix() *Matrix {
+	return affine(p.rotationMatrix().Times(p.stretch), p.translation)
+}
+
+// Returns the inverse of p.matrix(): undo translation, then rotation,
+// then stretch.
+func (p xformParts) inverse() *Matrix {
+	m := affine(p.stretch.inverse().Times(p.rotationMatrix().transpose()), Vector{})
+	t := m.TimesV(p.translation)
+	m.set(0, 3, -t.X)
+	m.set(1, 3, -t.Y)
+	m.set(2, 3, -t.Z)
+	return m
+}
+
+// Returns p's rotation as a 3x3 matrix.
+func (p xformParts) rotationMatrix() *Matrix {
+	r := p.rotation.rotation()
+	return MakeMatrix([][]float64{r[0][:], r[1][:], r[2][:]})
+}
+
+// Returns the 4x4 transform applying the 3x3 matrix linear, then
+// translating by t.
+func affine(linear *Matrix, t Vector) *Matrix {
+	m := MakeIdentity()
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m.set(r, c, linear.get(r, c))
+		}
+	}
+	m.set(0, 3, t.X)
+	m.set(1, 3, t.Y)
+	m.set(2, 3, t.Z)
+	return m
+}
+
+// A unit quaternion representing a rotation.
+type quaternion struct {
+	w, x, y, z float64
+}
+
+// Returns the quaternion for the rotation matrix r.
+func quaternionFromRotation(r [3][3]float64) quaternion {
+	var q quaternion
+	switch trace := r[0][0] + r[1][1] + r[2][2]; {
+	case trace > 0:
+		s := 2 * math.Sqrt(trace+1)
+		q = quaternion{s / 4, (r[2][1] - r[1][2]) / s, (r[0][2] - r[2][0]) / s, (r[1][0] - r[0][1]) / s}
+	case r[0][0] > r[1][1] && r[0][0] > r[2][2]:
+		s := 2 * math.Sqrt(1+r[0][0]-r[1][1]-r[2][2])
+		q = quaternion{(r[2][1] - r[1][2]) / s, s / 4, (r[0][1] + r[1][0]) / s, (r[0][2] + r[2][0]) / s}
+	case r[1][1] > r[2][2]:
+		s := 2 * math.Sqrt(1+r[1][1]-r[0][0]-r[2][2])
+		q = quaternion{(r[0][2] - r[2][0]) / s, (r[0][1] + r[1][0]) / s, s / 4, (r[1][2] + r[2][1]) / s}
+	default:
+		s := 2 * math.Sqrt(1+r[2][2]-r[0][0]-r[1][1])
+		q = quaternion{(r[1][0] - r[0][1]) / s, (r[0][2] + r[2][0]) / s, (r[1][2] + r[2][1]) / s, s / 4}
+	}
+	return q.norm()
+}
+
+// Returns the rotation matrix for q.
+func (q quaternion) rotation() [3][3]float64 {
+	w, x, y, z := q.w, q.x, q.y, q.z
+	return [3][3]float64{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y)},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x)},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y)},
+	}
+}
+
+func (q quaternion) dot(q2 quaternion) float64 {
+	return q.w*q2.w + q.x*q2.x + q.y*q2.y + q.z*q2.z
+}
+
+func (q quaternion) norm() quaternion {
+	l := math.Sqrt(q.dot(q))
+	return quaternion{q.w / l, q.x / l, q.y / l, q.z / l}
+}
+
+// Returns the rotation f of the way from q to q2, along the shorter arc.
+func (q quaternion) slerp(q2 quaternion, f float64) quaternion {
+	d := q.dot(q2)
+	if d < 0 {
+		q2, d = quaternion{-q2.w, -q2.x, -q2.y, -q2.z}, -d
+	}
+	a, b := 1-f, f
+	if d < 0.9995 {
+		// Otherwise nearly parallel, where linear blending is accurate.
+		theta := math.Acos(d)
+		a = math.Sin((1-f)*theta) / math.Sin(theta)
+		b = math.Sin(f*theta) / math.Sin(theta)
+	}
+	return quaternion{a*q.w + b*q2.w, a*q.x + b*q2.x, a*q.y + b*q2.y, a*q.z + b*q2.z}.norm()
+}
@@ -0,0 +1,214 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecomposeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix
+	}{
+		{"identity", MakeIdentity()},
+		{"translation", MakeTranslation(1, -2, 3)},
+		{"scale rotate translate", MakeScaling(1, 2, 3).RotateY(math.Pi/3).Translate(4, 5, 6)},
+		{"reflection", MakeScaling(-1, 2, 1).RotateX(2).RotateZ(1)},
+		{"half turn", MakeRotationZ(math.Pi)},
+		{"shear", MakeRotationZ(math.Pi/4).Scale(3, 1, 1).Translate(1, 2, 3)},
+		{"shear and reflection", MakeRotationX(1).Scale(1, -2, 0.5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := decompose(test.m)
+			if got, want := p.matrix(), test.m; !approxEq(got, want) {
+				t.Errorf("matrix: %s", approxError(got, want))
+			}
+			if got, want := p.inverse(), test.m.inverse(); !approxEq(got, want) {
+				t.Errorf("inverse: %s", approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestMotionAt(t *testing.T) {
+	m := newMotion([]Keyframe{
+		{2, MakeRotationZ(math.Pi/2).Translate(4, 0, 0)},
+		{0, MakeIdentity()},
+	})
+	tests := []struct {
+		name string
+		time float64
+		want *Matrix
+	}{
+		{"before first", -1, MakeIdentity()},
+		{"at first", 0, MakeIdentity()},
+		{"halfway", 1, MakeRotationZ(math.Pi/4).Translate(2, 0, 0)},
+		{"at last", 2, MakeRotationZ(math.Pi/2).Translate(4, 0, 0)},
+		{"after last", 3, MakeRotationZ(math.Pi/2).Translate(4, 0, 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			xf, ixf := m.at(test.time)
+			if !approxEq(xf, test.want) {
+				t.Errorf("xform: %s", approxError(xf, test.want))
+			}
+			if want := test.want.inverse(); !approxEq(ixf, want) {
+				t.Errorf("inverse: %s", approxError(ixf, want))
+			}
+		})
+	}
+}
+
+func TestMotionAtSheared(t *testing.T) {
+	sheared := MakeRotationZ(math.Pi/4).Scale(3, 1, 1)
+	tests := []struct {
+		name      string
+		keyframes []Keyframe
+		want      *Matrix
+	}{
+		{"holding still", []Keyframe{{0, sheared}, {1, sheared}}, sheared},
+		{"moving", []Keyframe{{0, sheared}, {1, sheared.Translate(4, 0, 0)}}, sheared.Translate(2, 0, 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			xf, ixf := newMotion(test.keyframes).at(0.5)
+			if !approxEq(xf, test.want) {
+				t.Errorf("xform: %s", approxError(xf, test.want))
+			}
+			if want := test.want.inverse(); !approxEq(ixf, want) {
+				t.Errorf("inverse: %s", approxError(ixf, want))
+			}
+		})
+	}
+}
+
+func TestMovingShapeSheared(t *testing.T) {
+	sheared := MakeRotationZ(math.Pi/4).Scale(3, 1, 1)
+	still := NewSphere(sheared)
+	moving := NewSphere(MakeIdentity())
+	moving.Animate(Keyframe{0, sheared}, Keyframe{1, sheared})
+	for _, r := range []Ray{
+		{Point{0, 1.5, -5}, Vector{0, 0, 1}, 0.5},
+		{Point{1, 1, -5}, Vector{0, 0, 1}, 0.5},
+		{Point{2, -0.5, -5}, Vector{0, 0, 1}, 0.5},
+	} {
+		got, want := moving.Intersect(r), still.Intersect(r)
+		if !approxEq(got, want) {
+			t.Errorf("ray %v: %s", r, approxError(got, want))
+		}
+	}
+}
+
+// Returns a unit sphere moving from the origin to (4, 0, 0) between
+// times 0 and 1.
+func movingSphere() Shape {
+	s := NewSphere(MakeIdentity())
+	s.Animate(Keyframe{0, MakeIdentity()}, Keyframe{1, MakeTranslation(4, 0, 0)})
+	return s
+}
+
+func TestMovingShapeIntersect(t *testing.T) {
+	tests := []struct {
+		name string
+		time float64
+		want []MaterialIntersection
+	}{
+		{"not there yet", 0, []MaterialIntersection{}},
+		{"passing", 0.5, []MaterialIntersection{{t: 4}, {t: 6}}},
+		{"gone past", 1, []MaterialIntersection{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := Ray{Point{2, 0, -5}, Vector{0, 0, 1}, test.time}
+			got := movingSphere().Intersect(r)
+			if !approxEq(got, test.want) {
+				t.Error(approxError(got, test.want))
+			}
+		})
+	}
+}
+
+func TestMovingShapeNormal(t *testing.T) {
+	s := NewSphere(MakeIdentity())
+	s.Animate(Keyframe{0, MakeIdentity()}, Keyframe{1, MakeScaling(1, 1, 2)})
+	// At time 1 the sphere is stretched along z, so its surface slopes
+	// more steeply where the ray meets it.
+	r := Ray{Point{0, 0.6, -5}, Vector{0, 0, 1}, 1}
+	xs := s.Intersect(r)
+	if len(xs) != 2 {
+		t.Fatalf("got %d intersections; want 2", len(xs))
+	}
+	got, want := xs[0].normalV, Vector{0, 0.6, -0.4}.Norm()
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestMovingShapeBounds(t *testing.T) {
+	s := NewSphere(MakeIdentity())
+	s.Animate(Keyframe{0, MakeIdentity()}, Keyframe{1, MakeRotationZ(math.Pi).Translate(4, 0, 0)})
+	b := s.Bounds()
+	for _, time := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		xf, _ := s.(*sphere).motion.at(time)
+		for _, p := range []Point{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}} {
+			wp := xf.TimesP(p)
+			if wp.X < b.Min.X || wp.X > b.Max.X || wp.Y < b.Min.Y || wp.Y > b.Max.Y {
+				t.Errorf("time %v: %v outside bounds %v", time, wp, b)
+			}
+		}
+	}
+}
+
+func TestMovingShapeInGroup(t *testing.T) {
+	g := NewGroup(MakeTranslation(0, 10, 0))
+	g.AddShape(movingSphere())
+	g.BuildBVH()
+	r := Ray{Point{4, 10, -5}, Vector{0, 0, 1}, 1}
+	if got := g.Intersect(r); len(got) != 2 {
+		t.Errorf("got %d intersections; want 2", len(got))
+	}
+	r.time = 0
+	if got := g.Intersect(r); len(got) != 0 {
+		t.Errorf("got %d intersections at time 0; want 0", len(got))
+	}
+}
+
+func TestMovingTriangle(t *testing.T) {
+	tests := []struct {
+		name string
+		tr   Shape
+	}{
+		{"flat", NewTriangle(Point{0, 1, 0}, Point{-1, 0, 0}, Point{1, 0, 0})},
+		{"smooth", NewSmoothTriangle(Point{0, 1, 0}, Point{-1, 0, 0}, Point{1, 0, 0}, Vector{0, 1, 0}, Vector{-1, 0, 0}, Vector{1, 0, 0})},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.tr.Animate(Keyframe{0, MakeIdentity()}, Keyframe{1, MakeTranslation(4, 0, 0)})
+			r := Ray{Point{4, 0.5, -2}, Vector{0, 0, 1}, 1}
+			got, want := test.tr.Intersect(r), 1
+			if len(got) != want {
+				t.Fatalf("got %d intersections; want %d", len(got), want)
+			}
+			if !approxEq(got[0].t, 2.0) {
+				t.Error(approxError(got[0].t, 2.0))
+			}
+			r.time = 0
+			if got := test.tr.Intersect(r); len(got) != 0 {
+				t.Errorf("got %d intersections at time 0; want 0", len(got))
+			}
+			if b := test.tr.Bounds(); b.Min.X > -1 || b.Max.X < 5 {
+				t.Errorf("bounds %v don't span the motion", b)
+			}
+		})
+	}
+}
+
+func TestAnimateWithoutKeyframes(t *testing.T) {
+	s := NewSphere(MakeTranslation(4, 0, 0))
+	assertNoPanic(t, func() { s.Animate() }, "Animate with no keyframes panicked")
+	got, want := s.Xform(), MakeTranslation(4, 0, 0)
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
@@ -0,0 +1,198 @@
+package raytracer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ObjFile holds the geometry read from a Wavefront OBJ file.
+type ObjFile struct {
+	Vertices      []Point
+	Normals       []Vector
+	TextureCoords [][2]float64
+	// Triangles from faces outside of any named group.
+	DefaultGroup *Group
+	// Triangles from faces following each g or o statement, by name.
+	Groups map[string]*Group
+	// Number of lines not understood by the parser, by first keyword.
+	Ignored map[string]int
+	// Material shared by every triangle in the file, so changing it
+	// changes the whole mesh. Sharing it also lets refraction tell when a
+	// ray leaves a closed mesh.
+	Material *Material
+	// Group names in order of first appearance.
+	groupNames []string
+}
+
+// Returns the number of lines not understood by the parser.
+func (o *ObjFile) IgnoredLines() int {
+	n := 0
+	for _, count := range o.Ignored {
+		n += count
+	}
+	return n
+}
+
+// Returns a single Group holding the default group and every named
+// group, in the order they appear in the file.
+func (o *ObjFile) Group() *Group {
+	g := NewGroup(MakeIdentity())
+	g.AddShape(o.DefaultGroup)
+	for _, name := range o.groupNames {
+		g.AddShape(o.Groups[name])
+	}
+	return g
+}
+
+// ParseObjFile reads the named Wavefront OBJ file.
+func ParseObjFile(filename string) (*ObjFile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseObj(f)
+}
+
+// ParseObj reads Wavefront OBJ data. Vertices (v), vertex normals (vn),
+// texture coordinates (vt), faces (f) and groups (g, o) are understood;
+// other statements are counted in ObjFile.Ignored. Polygonal faces are
+// split into a fan of triangles, smooth if the face has vertex normals.
+func ParseObj(r io.Reader) (*ObjFile, error) {
+	o := &ObjFile{
+		DefaultGroup: NewGroup(MakeIdentity()),
+		Groups:       map[string]*Group{},
+		Ignored:      map[string]int{},
+		Material:     NewMaterial(),
+	}
+	current := o.DefaultGroup
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "v":
+			var v []float64
+			if v, err = parseFloats(fields[1:], 3); err == nil {
+				o.Vertices = append(o.Vertices, Point{v[0], v[1], v[2]})
+			}
+		case "vn":
+			var v []float64
+			if v, err = parseFloats(fields[1:], 3); err == nil {
+				o.Normals = append(o.Normals, Vector{v[0], v[1], v[2]})
+			}
+		case "vt":
+			var v []float64
+			if v, err = parseFloats(fields[1:], 2); err == nil {
+				o.TextureCoords = append(o.TextureCoords, [2]float64{v[0], v[1]})
+			}
+		case "f":
+			err = o.parseFace(fields[1:], current)
+		case "g", "o":
+			name := strings.Join(fields[1:], " ")
+			g, ok := o.Groups[name]
+			if !ok {
+				g = NewGroup(MakeIdentity())
+				o.Groups[name] = g
+				o.groupNames = append(o.groupNames, name)
+			}
+			current = g
+		default:
+			o.Ignored[fields[0]]++
+		}
+		if err != nil {
+			return nil, fmt.Errorf("obj line %d: %v", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Parses at least n floats from fields, ignoring any beyond n.
+func parseFloats(fields []string, n int) ([]float64, error) {
+	if len(fields) < n {
+		return nil, fmt.Errorf("want %d values, got %d", n, len(fields))
+	}
+	vals := make([]float64, n)
+	for i := range vals {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// Adds the triangles of the face given by fields to g. Each field is a
+// vertex reference of the form v, v/vt, v//vn or v/vt/vn.
+func (o *ObjFile) parseFace(fields []string, g *Group) error {
+	if len(fields) < 3 {
+		return fmt.Errorf("face needs at least 3 vertices, got %d", len(fields))
+	}
+	points := make([]Point, len(fields))
+	normals := make([]Vector, len(fields))
+	smooth := true
+	for i, field := range fields {
+		refs := strings.Split(field, "/")
+		vi, err := objIndex(refs[0], len(o.Vertices))
+		if err != nil {
+			return err
+		}
+		points[i] = o.Vertices[vi]
+		if len(refs) > 1 && refs[1] != "" {
+			if _, err := objIndex(refs[1], len(o.TextureCoords)); err != nil {
+				return err
+			}
+		}
+		if len(refs) > 2 && refs[2] != "" {
+			ni, err := objIndex(refs[2], len(o.Normals))
+			if err != nil {
+				return err
+			}
+			normals[i] = o.Normals[ni]
+		} else {
+			smooth = false
+		}
+	}
+
+	// Fan triangulation around the first vertex.
+	for i := 1; i < len(points)-1; i++ {
+		tr := newTriangle(points[0], points[i], points[i+1])
+		tr.material = o.Material
+		if smooth {
+			g.AddShape(&smoothTriangle{tr, normals[0], normals[i], normals[i+1]})
+		} else {
+			g.AddShape(tr)
+		}
+	}
+	return nil
+}
+
+// Converts a 1-based (or negative, counting back from the end) OBJ
+// index into a list of length n into a 0-based index.
+func objIndex(s string, n int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		i += n + 1
+	}
+	if i < 1 || i > n {
+		return 0, fmt.Errorf("index %s out of range [1, %d]", s, n)
+	}
+	return i - 1, nil
+}
@@ -0,0 +1,255 @@
+package raytracer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseObjIgnoresUnrecognizedLines(t *testing.T) {
+	input := `There was a young lady named Bright
+who traveled much faster than light.
+
+# A comment, not counted.
+usemtl shiny
+usemtl dull
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := o.IgnoredLines(), 4; got != want {
+		t.Errorf("IgnoredLines() = %d; want %d", got, want)
+	}
+	if got, want := o.Ignored["usemtl"], 2; got != want {
+		t.Errorf("Ignored[usemtl] = %d; want %d", got, want)
+	}
+}
+
+func TestParseObjVertexData(t *testing.T) {
+	input := `v -1 1 0
+v -1.0000 0.5000 0.0000
+v 1 0 0
+v 1 1 0
+vn 0 0 1
+vn 0.707 0 -0.707
+vt 0.5 0.25
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantV := []Point{{-1, 1, 0}, {-1, 0.5, 0}, {1, 0, 0}, {1, 1, 0}}
+	if !approxEq(o.Vertices, wantV) {
+		t.Errorf("Vertices: %s", approxError(o.Vertices, wantV))
+	}
+	wantN := []Vector{{0, 0, 1}, {0.707, 0, -0.707}}
+	if !approxEq(o.Normals, wantN) {
+		t.Errorf("Normals: %s", approxError(o.Normals, wantN))
+	}
+	wantT := [][2]float64{{0.5, 0.25}}
+	if !approxEq(o.TextureCoords, wantT) {
+		t.Errorf("TextureCoords: %s", approxError(o.TextureCoords, wantT))
+	}
+}
+
+// Returns the vertices of each triangle in g.
+func trianglePoints(t *testing.T, g *Group) [][3]Point {
+	ps := [][3]Point{}
+	for _, s := range g.Shapes() {
+		switch tr := s.(type) {
+		case *triangle:
+			ps = append(ps, [3]Point{tr.p1, tr.p2, tr.p3})
+		case *smoothTriangle:
+			ps = append(ps, [3]Point{tr.p1, tr.p2, tr.p3})
+		default:
+			t.Fatalf("got shape %T; want triangle", s)
+		}
+	}
+	return ps
+}
+
+func TestParseObjFaces(t *testing.T) {
+	input := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+v 0 2 0
+
+f 1 2 3
+f 1 3 4
+f 1 2 3 4 5
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := o.Vertices
+	got := trianglePoints(t, o.DefaultGroup)
+	want := [][3]Point{
+		{v[0], v[1], v[2]},
+		{v[0], v[2], v[3]},
+		{v[0], v[1], v[2]},
+		{v[0], v[2], v[3]},
+		{v[0], v[3], v[4]},
+	}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestParseObjSharedMaterial(t *testing.T) {
+	input := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+vn 0 0 1
+f 1 2 3
+g second
+f 1//1 3//1 4//1
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range append(o.DefaultGroup.Shapes(), o.Groups["second"].Shapes()...) {
+		if s.Material() != o.Material {
+			t.Errorf("%T has material %p; want the file's %p", s, s.Material(), o.Material)
+		}
+	}
+}
+
+func TestParseObjRefractionThroughClosedMesh(t *testing.T) {
+	// A cube from -1 to 1.
+	input := `v -1 -1 -1
+v 1 -1 -1
+v 1 1 -1
+v -1 1 -1
+v -1 -1 1
+v 1 -1 1
+v 1 1 1
+v -1 1 1
+f 1 2 3 4
+f 5 8 7 6
+f 1 5 6 2
+f 4 3 7 8
+f 1 4 8 5
+f 2 6 7 3
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Material.Transparency = 1
+	o.Material.RefractiveIndex = 1.5
+	r := Ray{Point{0.3, 0.1, -5}, Vector{0, 0, 1}, 0}
+	xs := Intersections(o.Group().Intersect(r))
+	if len(xs) != 2 {
+		t.Fatalf("got %d intersections; want 2", len(xs))
+	}
+	// The same as through the Cube primitive.
+	want := [][2]float64{{1, 1.5}, {1.5, 1}}
+	for i, x := range xs {
+		if got := [2]float64{x.n1, x.n2}; !approxEq(got, want[i]) {
+			t.Errorf("intersection %d n1, n2: %s", i, approxError(got, want[i]))
+		}
+	}
+}
+
+func TestParseObjNamedGroups(t *testing.T) {
+	input := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+g FirstGroup
+f 1 2 3
+o SecondGroup
+f 1 3 4
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := o.Vertices
+	tests := []struct {
+		name string
+		want [][3]Point
+	}{
+		{"FirstGroup", [][3]Point{{v[0], v[1], v[2]}}},
+		{"SecondGroup", [][3]Point{{v[0], v[2], v[3]}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, ok := o.Groups[test.name]
+			if !ok {
+				t.Fatalf("missing group %s", test.name)
+			}
+			got := trianglePoints(t, g)
+			if !approxEq(got, test.want) {
+				t.Error(approxError(got, test.want))
+			}
+		})
+	}
+
+	all := o.Group().Shapes()
+	if len(all) != 3 || all[0] != o.DefaultGroup || all[1] != o.Groups["FirstGroup"] || all[2] != o.Groups["SecondGroup"] {
+		t.Errorf("Group() = %v; want default, FirstGroup, SecondGroup", all)
+	}
+}
+
+func TestParseObjFacesWithNormals(t *testing.T) {
+	input := `v 0 1 0
+v -1 0 0
+v 1 0 0
+vt 0 0
+vn -1 0 0
+vn 1 0 0
+vn 0 1 0
+
+f 1//3 2//1 3//2
+f 1/1/3 2/1/1 3/1/2
+f -3/-1/-1 -2/-1/-3 -1/-1/-2
+`
+	o, err := ParseObj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes := o.DefaultGroup.Shapes()
+	if len(shapes) != 3 {
+		t.Fatalf("got %d shapes; want 3", len(shapes))
+	}
+	for i, s := range shapes {
+		st, ok := s.(*smoothTriangle)
+		if !ok {
+			t.Fatalf("shape %d is %T; want smooth triangle", i, s)
+		}
+		got := [6]interface{}{st.p1, st.p2, st.p3, st.n1, st.n2, st.n3}
+		want := [6]interface{}{
+			o.Vertices[0], o.Vertices[1], o.Vertices[2],
+			o.Normals[2], o.Normals[0], o.Normals[1],
+		}
+		if !approxEq(got, want) {
+			t.Errorf("shape %d: %s", i, approxError(got, want))
+		}
+	}
+}
+
+func TestParseObjErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"bad vertex", "v 1 x 0\n"},
+		{"short vertex", "v 1 0\n"},
+		{"vertex out of range", "v 0 0 0\nv 1 0 0\nf 1 2 3\n"},
+		{"normal out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1//1 2//1 3//1\n"},
+		{"too few face vertices", "v 0 0 0\nv 1 0 0\nf 1 2\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseObj(strings.NewReader(test.input)); err == nil {
+				t.Errorf("got nil error; want error")
+			}
+		})
+	}
+}
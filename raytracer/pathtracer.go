@@ -0,0 +1,128 @@
+package raytracer
+
+import "math"
+
+// Number of bounces a path makes before Russian roulette may end it.
+const rouletteDepth = 3
+
+// pathTracer colors rays by Monte Carlo path tracing, following random
+// paths of light as it bounces around the World.
+type pathTracer struct {
+	// Number of paths averaged for each ray.
+	samples int
+	// Seeds the choice of paths.
+	seed int64
+}
+
+// NewPathTracer returns a shader that lights surfaces by each other as
+// well as by the World's lights, so light bounces indirectly and colors
+// bleed from one surface onto the next, and materials' Emissive light
+// makes them light sources. Each ray's color averages the given number
+// of paths, so each pixel averages that many per camera ray. At each
+// bounce a path gathers light straight from the World's lights, then
+// continues by a diffuse bounce, mirror reflection or refraction, chosen
+// at random in proportion to how much light each carries. Paths bounce
+// at most depth times (RenderOptions.MaxDepth), and past a few bounces
+// end at random, the more likely the less light they still carry. Paths
+// are random, but the same for the same seed and ray. Materials' Ambient
+// is ignored.
+func NewPathTracer(samples int, seed int64) Shader {
+	return pathTracer{samples, seed}
+}
+
+func (pt pathTracer) ColorAt(w World, r Ray, depth int, stats *RenderStats) Color {
+	samples := pt.samples
+	if samples < 1 {
+		samples = 1
+	}
+	rnd := newRayRand(pt.seed, r)
+	sum := Black()
+	for i := 0; i < samples; i++ {
+		sum = sum.plus(pt.tracePath(w, r, depth, rnd, stats))
+	}
+	return sum.scale(1 / float64(samples))
+}
+
+// Returns the light carried back along r by one random path of at most
+// depth bounces.
+func (pt pathTracer) tracePath(w World, r Ray, depth int, rnd *sampleRand, stats *RenderStats) Color {
+	c := Black()
+	// Fraction of the light arriving at the current hit that reaches the
+	// eye along the path.
+	throughput := White()
+	for bounce := 0; ; bounce++ {
+		x := hit(Intersections(w.Intersect(r)))
+		if x == nil {
+			return c.plus(throughput.times(w.Background().Radiance(r.dir)))
+		}
+		c = c.plus(throughput.times(x.material.Emissive.plus(pt.directLight(w, x))))
+		if bounce >= depth {
+			stats.DepthLimitHits++
+			return c
+		}
+		if bounce >= rouletteDepth {
+			// Russian roulette: end the path with probability 1-q, and
+			// make up for the paths ended by weighting the survivors.
+			q := math.Min(throughput.maxComponent(), 0.95)
+			if rnd.next() >= q {
+				return c
+			}
+			throughput = throughput.scale(1 / q)
+		}
+		next, weight, ok := pt.scatter(x, rnd)
+		if !ok {
+			return c
+		}
+		throughput = throughput.times(weight)
+		r = next
+	}
+}
+
+// Returns the Phong-lit color at x from the World's lights, without the
+// ambient term.
+func (pt pathTracer) directLight(w World, x *Intersection) Color {
+	m := *x.material
+	m.Ambient = 0
+	c := Black()
+	for _, l := range w.Lights() {
+		visibility := w.LightVisibility(x.overPoint, l, x.time)
+		c = c.plus(Lighting(&m, l, x.overPoint, x.eyeV, x.normalV, visibility))
+	}
+	return c
+}
+
+// Chooses how a path continues from x: by a diffuse bounce in a
+// cosine-weighted direction, by mirror reflection, or by refraction.
+// Returns the next ray and the color the light it brings is weighted by,
+// or false if x passes on no light.
+func (pt pathTracer) scatter(x *Intersection, rnd *sampleRand) (Ray, Color, bool) {
+	m := x.material
+	diffuse := m.Color.scale(m.Diffuse)
+	reflective, transparency := m.Reflective, m.Transparency
+	refractV, ok := x.refractV()
+	if !ok {
+		// Total internal reflection.
+		transparency = 0
+	}
+	if reflective > 0 && transparency > 0 {
+		reflectance := x.schlick()
+		reflective, transparency = reflective*reflectance, transparency*(1-reflectance)
+	}
+
+	pDiffuse := diffuse.luminance()
+	total := pDiffuse + reflective + transparency
+	if total <= 0 {
+		return Ray{}, Black(), false
+	}
+	switch u := rnd.next() * total; {
+	case u < pDiffuse:
+		// The cosine in the rendering equation cancels with the sampling
+		// density, leaving the diffuse color.
+		dir := sampleHemisphere(rnd.next(), rnd.next(), x.normalV)
+		return Ray{x.overPoint, dir, x.time}, diffuse.scale(total / pDiffuse), true
+	case u < pDiffuse+reflective:
+		return Ray{x.overPoint, x.reflectV, x.time}, Color{total, total, total}, true
+	default:
+		return Ray{x.underPoint, refractV, x.time}, Color{total, total, total}, true
+	}
+}
@@ -0,0 +1,165 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns a world with a floor of the given diffuse color under a white
+// sky, and a ray looking straight down at it.
+func skyFloorWorld(diffuse float64) (World, Ray) {
+	w := NewEmptyWorld()
+	w.SetBackground(SolidBackground{White()})
+	floor := NewPlane(MakeIdentity())
+	floor.Material().Diffuse = diffuse
+	floor.Material().Specular = 0
+	w.AddShape(floor)
+	return w, Ray{Point{0, 1, 0}, Vector{0, -1, 0}, 0}
+}
+
+func TestPathTracer(t *testing.T) {
+	tests := []struct {
+		name          string
+		world         func() (World, Ray)
+		depth         int
+		want          Color
+		wantLimitHits int
+	}{
+		{
+			name: "miss sees background",
+			world: func() (World, Ray) {
+				w := NewEmptyWorld()
+				w.SetBackground(GradientBackground{Black(), White()})
+				return w, Ray{Point{0, 0, 0}, Vector{0, 1, 0}, 0}
+			},
+			depth: DefaultMaxDepth,
+			want:  White(),
+		},
+		{
+			name: "emissive surface",
+			world: func() (World, Ray) {
+				w := NewEmptyWorld()
+				s := NewSphere(MakeIdentity())
+				ambientMaterial(s.Material(), White())
+				s.Material().Emissive = Color{0.2, 0.4, 0.6}
+				w.AddShape(s)
+				return w, Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+			},
+			depth: DefaultMaxDepth,
+			// Ambient is ignored, and the sphere has nothing to reflect.
+			want: Color{0.2, 0.4, 0.6},
+		},
+		{
+			name: "diffuse bounce to the sky",
+			world: func() (World, Ray) {
+				return skyFloorWorld(0.5)
+			},
+			depth: DefaultMaxDepth,
+			// Every bounce off the floor escapes to the sky.
+			want: Color{0.5, 0.5, 0.5},
+		},
+		{
+			name: "no bounces left",
+			world: func() (World, Ray) {
+				return skyFloorWorld(0.5)
+			},
+			depth:         0,
+			want:          Black(),
+			wantLimitHits: 1,
+		},
+		{
+			name: "mirror reflects an emissive sphere",
+			world: func() (World, Ray) {
+				w := NewEmptyWorld()
+				mirror := NewSphere(MakeIdentity())
+				ambientMaterial(mirror.Material(), Black())
+				mirror.Material().Reflective = 1
+				w.AddShape(mirror)
+				target := NewSphere(MakeTranslation(0, 0, -10))
+				ambientMaterial(target.Material(), Black())
+				target.Material().Emissive = Red()
+				w.AddShape(target)
+				return w, Ray{Point{0, 0, -3}, Vector{0, 0, 1}, 0}
+			},
+			depth: DefaultMaxDepth,
+			want:  Red(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, r := test.world()
+			stats := RenderStats{}
+			got, want := NewPathTracer(16, 1).ColorAt(w, r, test.depth, &stats), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+			if stats.DepthLimitHits != test.wantLimitHits*16 {
+				t.Errorf("DepthLimitHits = %d; want %d", stats.DepthLimitHits, test.wantLimitHits*16)
+			}
+		})
+	}
+}
+
+func TestPathTracerDirectLight(t *testing.T) {
+	// With nothing else to light it, the floor is lit just as the light
+	// shader lights it, without ambient.
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{-2, 5, -3}, White()))
+	floor := NewPlane(MakeIdentity())
+	floor.Material().Color = Color{1, 0.5, 0.25}
+	w.AddShape(floor)
+	r := Ray{Point{0, 1, -1}, Vector{0, -1, 1}.Norm(), 0}
+
+	got := NewPathTracer(4, 1).ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+	floor.Material().Ambient = 0
+	want := NewLightShader().ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestPathTracerInterreflection(t *testing.T) {
+	// Inside a glowing sphere that reflects half the light reaching it,
+	// light bounces back and forth until it builds up to twice the glow.
+	w := NewEmptyWorld()
+	s := NewSphere(MakeIdentity())
+	s.Material().Ambient = 0
+	s.Material().Diffuse = 0.5
+	s.Material().Specular = 0
+	s.Material().Emissive = Color{0.5, 0.5, 0.5}
+	w.AddShape(s)
+	r := Ray{Point{0, 0, 0}, Vector{0, 0, 1}, 0}
+
+	got := NewPathTracer(2000, 1).ColorAt(w, r, 30, &RenderStats{})
+	if math.Abs(got.R-1) > 0.05 || got.G != got.R || got.B != got.R {
+		t.Errorf("got %v; want gray 1", got)
+	}
+}
+
+func TestPathTracerRepeatable(t *testing.T) {
+	w := NewDefaultWorld()
+	r := Ray{Point{0, 0, -5}, Vector{0.1, 0, 1}.Norm(), 0}
+	a := NewPathTracer(8, 3).ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+	b := NewPathTracer(8, 3).ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+	if a != b {
+		t.Errorf("same seed gave %v, then %v", a, b)
+	}
+}
+
+func TestSampleHemisphere(t *testing.T) {
+	n := Vector{1, 2, -2}.Norm()
+	rnd := newPixelRand(2, 0, 0)
+	count := 4000
+	sum := 0.0
+	for i := 0; i < count; i++ {
+		v := sampleHemisphere(rnd.next(), rnd.next(), n)
+		if !approxEq(v.Magnitude(), 1.0) || v.Dot(n) < 0 {
+			t.Fatalf("sampleHemisphere returned %v, not a unit vector around %v", v, n)
+		}
+		sum += v.Dot(n)
+	}
+	// The mean cosine of cosine-weighted directions is 2/3.
+	if mean := sum / float64(count); math.Abs(mean-2.0/3) > 0.02 {
+		t.Errorf("mean cosine %v; want 2/3", mean)
+	}
+}
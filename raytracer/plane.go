@@ -0,0 +1,36 @@
+package raytracer
+
+import "math"
+
+// A plane is the infinite xz plane through the origin, in object space.
+type plane struct {
+	shapeBase
+}
+
+func NewPlane(m *Matrix) Shape {
+	return &plane{newShapeBase(m)}
+}
+
+func (p *plane) Intersect(r Ray) []MaterialIntersection {
+	xfray := r.xform(p.ixfAt(r.time))
+	if math.Abs(xfray.dir.Y) < epsilon {
+		// Parallel or coplanar rays never cross the plane.
+		return []MaterialIntersection{}
+	}
+	t := -xfray.orig.Y / xfray.dir.Y
+	normalV := p.normalToWorld(p.localNormalAt(xfray.position(t)), r.time)
+	return []MaterialIntersection{NewMaterialIntersection(r, t, normalV, p.material)}
+}
+
+func (p *plane) Bounds() Bounds {
+	inf := math.Inf(1)
+	return p.parentBounds(Bounds{Point{-inf, 0, -inf}, Point{inf, 0, inf}})
+}
+
+func (p *plane) NormalAt(worldPoint Point) Vector {
+	return p.normalToWorld(p.localNormalAt(p.worldToObject(worldPoint, 0)), 0)
+}
+
+func (p *plane) localNormalAt(objectPoint Point) Vector {
+	return Vector{0, 1, 0}
+}
@@ -0,0 +1,91 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlaneNormalAt(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Shape
+		p    Point
+		want Vector
+	}{
+		{
+			name: "at origin",
+			s:    NewPlane(MakeIdentity()),
+			p:    Point{0, 0, 0},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "away from origin",
+			s:    NewPlane(MakeIdentity()),
+			p:    Point{-5, 0, 150},
+			want: Vector{0, 1, 0},
+		},
+		{
+			name: "on rotated plane",
+			s:    NewPlane(MakeRotationZ(math.Pi / 2)),
+			p:    Point{0, 3, 0},
+			want: Vector{-1, 0, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := test.s.NormalAt(test.p), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestPlaneIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		xf   *Matrix
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "parallel ray",
+			xf:   MakeIdentity(),
+			r:    Ray{Point{0, 10, 0}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "coplanar ray",
+			xf:   MakeIdentity(),
+			r:    Ray{Point{0, 0, 0}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "ray from above",
+			xf:   MakeIdentity(),
+			r:    Ray{Point{0, 1, 0}, Vector{0, -1, 0}, 0},
+			want: []MaterialIntersection{{t: 1}},
+		},
+		{
+			name: "ray from below",
+			xf:   MakeIdentity(),
+			r:    Ray{Point{0, -1, 0}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{{t: 1}},
+		},
+		{
+			name: "translated plane",
+			xf:   MakeTranslation(0, -2, 0),
+			r:    Ray{Point{0, 1, 0}, Vector{0, -1, 0}, 0},
+			want: []MaterialIntersection{{t: 3}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewPlane(test.xf)
+			got, want := s.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package raytracer
+
+import (
+	"errors"
+	"math"
+)
+
+// A Projection maps points on a camera's canvas to rays in camera space,
+// where the camera looks toward -z with +y up.
+type Projection interface {
+	// Returns the origin and direction (not necessarily normalized) of
+	// the ray through canvas point (px, py) of cam, and false if the
+	// projection covers no ray there.
+	Project(cam *Camera, px, py float64) (Point, Vector, bool)
+}
+
+// Implemented by projections with settings that can be invalid.
+type projectionValidator interface {
+	// Returns an error describing why the settings can't be rendered, or
+	// nil.
+	validate() error
+}
+
+// PerspectiveProjection is a pinhole view onto flat film, FieldOfView
+// wide.
+type PerspectiveProjection struct{}
+
+func (PerspectiveProjection) Project(cam *Camera, px, py float64) (Point, Vector, bool) {
+	film := cam.filmPoint(px, py)
+	return Point{0, 0, 0}, Vector{film.X, film.Y, film.Z}, true
+}
+
+// OrthographicProjection casts parallel rays from film Size world units
+// across its larger dimension, so sizes don't shrink with distance. Size
+// must be positive.
+type OrthographicProjection struct {
+	Size float64
+}
+
+func (p OrthographicProjection) validate() error {
+	if p.Size <= 0 {
+		return errors.New("orthographic projection: Size must be positive")
+	}
+	return nil
+}
+
+func (p OrthographicProjection) Project(cam *Camera, px, py float64) (Point, Vector, bool) {
+	scale := p.Size / cam.maxPixels()
+	// As in perspective, camera space x runs right to left.
+	x := (float64(cam.Width)/2 - px) * scale
+	y := (float64(cam.Height)/2 - py) * scale
+	return Point{x, y, 0}, Vector{0, 0, -1}, true
+}
+
+// FisheyeProjection is an equidistant fisheye: the angle of a ray from
+// the view direction grows in proportion to its distance from the center
+// of the canvas, reaching FieldOfView/2 at the middle of the edges of
+// the larger dimension. FieldOfView can exceed π; canvas points that
+// would look more than π from the view direction have no ray.
+type FisheyeProjection struct{}
+
+func (FisheyeProjection) Project(cam *Camera, px, py float64) (Point, Vector, bool) {
+	dx := float64(cam.Width)/2 - px
+	dy := float64(cam.Height)/2 - py
+	theta := math.Hypot(dx, dy) / (cam.maxPixels() / 2) * cam.FieldOfView / 2
+	if theta > math.Pi {
+		return Point{0, 0, 0}, Vector{0, 0, -1}, false
+	}
+	phi := math.Atan2(dy, dx)
+	sinTheta := math.Sin(theta)
+	return Point{0, 0, 0}, Vector{sinTheta * math.Cos(phi), sinTheta * math.Sin(phi), -math.Cos(theta)}, true
+}
+
+// EquirectangularProjection covers the whole sphere of directions, with
+// longitude running across the canvas and latitude down it, the view
+// direction at the center. A 2:1 canvas gives square pixels. FieldOfView
+// is not used.
+type EquirectangularProjection struct{}
+
+func (EquirectangularProjection) Project(cam *Camera, px, py float64) (Point, Vector, bool) {
+	longitude := (px/float64(cam.Width) - 0.5) * 2 * math.Pi
+	latitude := (0.5 - py/float64(cam.Height)) * math.Pi
+	cosLat := math.Cos(latitude)
+	return Point{0, 0, 0}, Vector{-math.Sin(longitude) * cosLat, math.Sin(latitude), -math.Cos(longitude) * cosLat}, true
+}
@@ -0,0 +1,135 @@
+package raytracer
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestProjections(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             Projection
+		width, height int
+		fov           float64
+		px, py        float64
+		wantOrigin    Point
+		wantDir       Vector
+		wantOK        bool
+	}{
+		{
+			name: "perspective corner", p: PerspectiveProjection{},
+			width: 201, height: 101, fov: math.Pi / 2, px: 0.5, py: 0.5,
+			wantDir: Vector{0.99502, 0.49751, -1}, wantOK: true,
+		},
+		{
+			name: "orthographic corner", p: OrthographicProjection{Size: 2},
+			width: 4, height: 2, px: 0.5, py: 0.5,
+			wantOrigin: Point{0.75, 0.25, 0}, wantDir: Vector{0, 0, -1}, wantOK: true,
+		},
+		{
+			name: "orthographic center", p: OrthographicProjection{Size: 2},
+			width: 4, height: 2, px: 2, py: 1,
+			wantDir: Vector{0, 0, -1}, wantOK: true,
+		},
+		{
+			name: "fisheye center", p: FisheyeProjection{},
+			width: 100, height: 100, fov: math.Pi, px: 50, py: 50,
+			wantDir: Vector{0, 0, -1}, wantOK: true,
+		},
+		{
+			name: "fisheye right edge", p: FisheyeProjection{},
+			width: 100, height: 100, fov: math.Pi, px: 100, py: 50,
+			wantDir: Vector{-1, 0, 0}, wantOK: true,
+		},
+		{
+			name: "fisheye top edge", p: FisheyeProjection{},
+			width: 100, height: 100, fov: math.Pi, px: 50, py: 0,
+			wantDir: Vector{0, 1, 0}, wantOK: true,
+		},
+		{
+			name: "fisheye halfway", p: FisheyeProjection{},
+			width: 100, height: 100, fov: math.Pi, px: 25, py: 50,
+			wantDir: Vector{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2}, wantOK: true,
+		},
+		{
+			name: "fisheye beyond behind", p: FisheyeProjection{},
+			width: 100, height: 100, fov: 2 * math.Pi, px: 0, py: 0,
+			wantDir: Vector{0, 0, -1}, wantOK: false,
+		},
+		{
+			name: "equirectangular center", p: EquirectangularProjection{},
+			width: 200, height: 100, px: 100, py: 50,
+			wantDir: Vector{0, 0, -1}, wantOK: true,
+		},
+		{
+			name: "equirectangular right", p: EquirectangularProjection{},
+			width: 200, height: 100, px: 150, py: 50,
+			wantDir: Vector{-1, 0, 0}, wantOK: true,
+		},
+		{
+			name: "equirectangular left edge", p: EquirectangularProjection{},
+			width: 200, height: 100, px: 0, py: 50,
+			wantDir: Vector{0, 0, 1}, wantOK: true,
+		},
+		{
+			name: "equirectangular top", p: EquirectangularProjection{},
+			width: 200, height: 100, px: 100, py: 0,
+			wantDir: Vector{0, 1, 0}, wantOK: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCamera(test.width, test.height, test.fov, MakeIdentity())
+			origin, dir, ok := test.p.Project(c, test.px, test.py)
+			if ok != test.wantOK {
+				t.Fatalf("ok = %v; want %v", ok, test.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !approxEq(origin, test.wantOrigin) {
+				t.Errorf("origin: %s", approxError(origin, test.wantOrigin))
+			}
+			if !approxEq(dir, test.wantDir) {
+				t.Errorf("direction: %s", approxError(dir, test.wantDir))
+			}
+		})
+	}
+}
+
+func TestCameraOrthographicRay(t *testing.T) {
+	c := NewCamera(4, 2, 0, MakeTranslation(0, -2, 5).RotateY(math.Pi/4))
+	c.Projection = OrthographicProjection{Size: 2}
+	got, ok := c.sampleRay(2, 1, newPixelRand(1, 2, 1))
+	want := Ray{Point{0, 2, -5}, Vector{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2}, 0}
+	if !ok || !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestRenderOrthographicWithoutSize(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2, MakeIdentity())
+	c.Projection = OrthographicProjection{}
+	if _, _, err := c.RenderContext(context.Background(), NewDefaultWorld(), RenderOptions{}); err == nil {
+		t.Error("got nil error; want one for Size 0")
+	}
+}
+
+func TestRenderFisheyeOutsideImageCircle(t *testing.T) {
+	c := NewCamera(11, 11, 2*math.Pi, MakeViewTransform(Point{0, 0, -5}, Point{0, 0, 0}, Vector{0, 1, 0}))
+	c.Projection = FisheyeProjection{}
+	w := NewDefaultWorld()
+	// Fill the background so black only comes from missing rays.
+	w.AddShape(NewSphere(MakeScaling(50, 50, 50)))
+	image, stats := c.RenderWithOptions(w, DefaultRenderOptions())
+	if got := image.Get(0, 0); got != Black() {
+		t.Errorf("corner = %v; want black", got)
+	}
+	if got := image.Get(5, 0); got == Black() {
+		t.Errorf("top edge is black; want the scene")
+	}
+	if stats.Samples >= 11*11 {
+		t.Errorf("Samples = %d; want fewer than %d", stats.Samples, 11*11)
+	}
+}
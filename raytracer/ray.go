@@ -3,6 +3,8 @@ package raytracer
 type Ray struct {
 	orig Point
 	dir  Vector
+	// When the ray is cast, for moving shapes.
+	time float64
 }
 
 func (r Ray) position(t float64) Point {
@@ -10,5 +12,5 @@ func (r Ray) position(t float64) Point {
 }
 
 func (r Ray) xform(m *Matrix) Ray {
-	return Ray{m.TimesP(r.orig), m.TimesV(r.dir)}
+	return Ray{m.TimesP(r.orig), m.TimesV(r.dir), r.time}
 }
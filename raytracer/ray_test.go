@@ -6,7 +6,7 @@ import (
 )
 
 func TestRayAtT(t *testing.T) {
-	r := Ray{Point{2, 3, 4}, Vector{1, 0, 0}}
+	r := Ray{Point{2, 3, 4}, Vector{1, 0, 0}, 0}
 
 	tests := []struct {
 		t    float64
@@ -47,15 +47,15 @@ func TestRayXform(t *testing.T) {
 	}{
 		{
 			name: "translate",
-			r:    Ray{Point{1, 2, 3}, Vector{0, 1, 0}},
+			r:    Ray{Point{1, 2, 3}, Vector{0, 1, 0}, 0},
 			xf:   MakeTranslation(3, 4, 5),
-			want: Ray{Point{4, 6, 8}, Vector{0, 1, 0}},
+			want: Ray{Point{4, 6, 8}, Vector{0, 1, 0}, 0},
 		},
 		{
 			name: "scale",
-			r:    Ray{Point{1, 2, 3}, Vector{0, 1, 0}},
+			r:    Ray{Point{1, 2, 3}, Vector{0, 1, 0}, 0},
 			xf:   MakeScaling(2, 3, 4),
-			want: Ray{Point{2, 6, 12}, Vector{0, 3, 0}},
+			want: Ray{Point{2, 6, 12}, Vector{0, 3, 0}, 0},
 		},
 	}
 	for _, test := range tests {
@@ -0,0 +1,351 @@
+package raytracer
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Default recursion limit for secondary rays.
+const DefaultMaxDepth = 5
+
+// RenderOptions configure a single render.
+type RenderOptions struct {
+	// Colors each camera ray; nil means NewLightShader().
+	Shader Shader
+	// Maximum number of nested secondary rays (reflected or refracted)
+	// traced from each camera ray; for NewPathTracer, the most bounces
+	// along each path.
+	MaxDepth int
+	// Number of goroutines rendering rows in parallel; 0 means
+	// runtime.NumCPU(). The image is the same for any number.
+	Workers int
+	// If set, called after each row is rendered. Calls are never
+	// concurrent, but come from rendering goroutines, so should be quick.
+	Progress func(RenderProgress)
+	// Number of samples along each axis of a pixel, so each pixel is the
+	// filtered average of Samples×Samples rays; 0 or 1 traces a single
+	// ray through the pixel center.
+	Samples int
+	// Where samples fall within each cell of the sample grid.
+	Sampling SamplingPattern
+	// Weights samples by their offset from the pixel center, and sets how
+	// far from the center they are spread; nil means BoxFilter{}.
+	Filter Filter
+	// Seeds random sample placement. Each pixel draws from its own
+	// sequence, so a render repeats exactly for the same Seed.
+	Seed int64
+	// If positive, samples adaptively: each pixel starts with a
+	// Samples×Samples grid (at least 2×2, but no more than MaxSamples
+	// samples) and takes further jittered grids of the same size until
+	// the standard error of its sample luminance falls below
+	// AdaptiveThreshold, or it has MaxSamples.
+	AdaptiveThreshold float64
+	// Most samples taken for any one pixel when sampling adaptively; 0
+	// means DefaultMaxSamples.
+	MaxSamples int
+}
+
+// Default cap on samples per pixel for adaptive sampling.
+const DefaultMaxSamples = 64
+
+// RenderProgress reports how far a render has got.
+type RenderProgress struct {
+	PixelsDone, TotalPixels int
+	Elapsed                 time.Duration
+	// Estimated time until the render completes, assuming the remaining
+	// pixels render at the average rate so far.
+	ETA time.Duration
+}
+
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Shader:   NewLightShader(),
+		MaxDepth: DefaultMaxDepth,
+	}
+}
+
+// RenderStats report counts accumulated over a single render.
+type RenderStats struct {
+	// Number of secondary rays not traced because MaxDepth was reached.
+	DepthLimitHits int
+	// Number of camera rays traced.
+	Samples int
+}
+
+func (s *RenderStats) add(s2 RenderStats) {
+	s.DepthLimitHits += s2.DepthLimitHits
+	s.Samples += s2.Samples
+}
+
+// Renders a view of the given World onto a canvas of the configured
+// size using the configured camera view. Panics if the camera's settings
+// are invalid.
+func (cam *Camera) Render(w World) *Canvas {
+	c, _ := cam.RenderWithOptions(w, DefaultRenderOptions())
+	return c
+}
+
+// Renders a view of the given World as Render does, using the given
+// options, and reports statistics about the render.
+func (cam *Camera) RenderWithOptions(w World, opts RenderOptions) (*Canvas, RenderStats) {
+	c, stats, err := cam.RenderContext(context.Background(), w, opts)
+	if err != nil {
+		// The context is never done, so the camera is invalid.
+		panic(err)
+	}
+	return c, stats
+}
+
+// Renders as RenderWithOptions does, stopping early if ctx is done. The
+// context is checked before each row, so a cancelled render returns the
+// rows finished so far, with the rest black, and ctx.Err(). If the
+// camera's settings are invalid, returns a nil canvas and an error
+// saying why.
+func (cam *Camera) RenderContext(ctx context.Context, w World, opts RenderOptions) (*Canvas, RenderStats, error) {
+	return cam.render(ctx, w, opts, nil)
+}
+
+// Renders as RenderContext does, and also returns a heatmap of how many
+// samples each pixel took, from black for none to white for the most
+// any pixel may take. Useful for tuning AdaptiveThreshold.
+func (cam *Camera) RenderWithHeatmap(ctx context.Context, w World, opts RenderOptions) (image, heatmap *Canvas, stats RenderStats, err error) {
+	counts := make([]int, cam.Width*cam.Height)
+	image, stats, err = cam.render(ctx, w, opts, counts)
+	if image == nil {
+		return nil, nil, stats, err
+	}
+	heatmap = MakeCanvas(cam.Width, cam.Height)
+	max := float64(opts.maxSamples())
+	for y := 0; y < cam.Height; y++ {
+		for x := 0; x < cam.Width; x++ {
+			v := float64(counts[y*cam.Width+x]) / max
+			heatmap.Set(x, y, Color{v, v, v})
+		}
+	}
+	return image, heatmap, stats, err
+}
+
+// Renders, recording the number of samples taken for each pixel in
+// counts, in row order, if it is not nil.
+func (cam *Camera) render(ctx context.Context, w World, opts RenderOptions, counts []int) (*Canvas, RenderStats, error) {
+	if err := cam.validate(); err != nil {
+		return nil, RenderStats{}, err
+	}
+	shader := opts.Shader
+	if shader == nil {
+		shader = NewLightShader()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	c := MakeCanvas(cam.Width, cam.Height)
+	start := time.Now()
+	// Guards rowsDone and calls to opts.Progress.
+	var mu sync.Mutex
+	rowsDone := 0
+	finishRow := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		rowsDone++
+		if opts.Progress != nil {
+			opts.Progress(newRenderProgress(rowsDone*c.Width, c.Width*c.Height, time.Since(start)))
+		}
+	}
+
+	rows := make(chan int)
+	// Each worker keeps its own stats, combined once all are done.
+	workerStats := make([]RenderStats, workers)
+	var wg sync.WaitGroup
+	for i := range workerStats {
+		wg.Add(1)
+		go func(stats *RenderStats) {
+			defer wg.Done()
+			for y := range rows {
+				if ctx.Err() != nil {
+					continue
+				}
+				for x := 0; x < c.Width; x++ {
+					color, n := cam.pixelColor(w, shader, opts, x, y, stats)
+					c.Set(x, y, color)
+					if counts != nil {
+						counts[y*c.Width+x] = n
+					}
+				}
+				finishRow()
+			}
+		}(&workerStats[i])
+	}
+feed:
+	for y := 0; y < c.Height; y++ {
+		select {
+		case rows <- y:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(rows)
+	wg.Wait()
+
+	stats := RenderStats{}
+	for _, s := range workerStats {
+		stats.add(s)
+	}
+	if rowsDone < c.Height {
+		return c, stats, ctx.Err()
+	}
+	return c, stats, nil
+}
+
+// Returns the most samples a pixel may take with these options.
+func (opts RenderOptions) maxSamples() int {
+	switch {
+	case opts.AdaptiveThreshold > 0 && opts.MaxSamples > 0:
+		return opts.MaxSamples
+	case opts.AdaptiveThreshold > 0:
+		return DefaultMaxSamples
+	case opts.Samples > 1:
+		return opts.Samples * opts.Samples
+	}
+	return 1
+}
+
+// Returns the color of pixel (x, y), and the number of samples taken for
+// it, combining samples spread over the filter's footprint around the
+// pixel center.
+func (cam *Camera) pixelColor(w World, shader Shader, opts RenderOptions, x, y int, stats *RenderStats) (Color, int) {
+	if opts.Samples <= 1 && opts.AdaptiveThreshold <= 0 {
+		r, ok := cam.sampleRay(float64(x)+0.5, float64(y)+0.5, newPixelRand(opts.Seed, x, y))
+		if !ok {
+			return Black(), 0
+		}
+		stats.Samples++
+		return shader.ColorAt(w, r, opts.MaxDepth, stats), 1
+	}
+	ps := pixelSampler{
+		cam:    cam,
+		w:      w,
+		shader: shader,
+		opts:   opts,
+		filter: opts.Filter,
+		rnd:    newPixelRand(opts.Seed, x, y),
+		stats:  stats,
+		cx:     float64(x) + 0.5,
+		cy:     float64(y) + 0.5,
+	}
+	if ps.filter == nil {
+		ps.filter = BoxFilter{}
+	}
+	n := opts.Samples
+	if opts.AdaptiveThreshold <= 0 {
+		ps.sampleGrid(n, opts.Sampling == Jittered)
+		return ps.color(), ps.count
+	}
+
+	if n < 2 {
+		n = 2
+	}
+	max := opts.maxSamples()
+	for n > 1 && n*n > max {
+		n--
+	}
+	ps.sampleGrid(n, opts.Sampling == Jittered)
+	for ps.count+n*n <= max && ps.stdErr() > opts.AdaptiveThreshold {
+		ps.sampleGrid(n, true)
+	}
+	return ps.color(), ps.count
+}
+
+// pixelSampler accumulates the filtered samples for one pixel.
+type pixelSampler struct {
+	cam    *Camera
+	w      World
+	shader Shader
+	opts   RenderOptions
+	filter Filter
+	rnd    *sampleRand
+	stats  *RenderStats
+	// Pixel center, in canvas coordinates.
+	cx, cy float64
+
+	sum     Color
+	weights float64
+	count   int
+	// Running mean and sum of squared differences from the mean of the
+	// samples' luminance.
+	mean, m2 float64
+}
+
+// Takes one sample in each cell of an n×n grid over the filter's
+// footprint, at the cell center or, if jitter is set, a random point in
+// the cell. Samples the filter gives no weight to are skipped.
+func (ps *pixelSampler) sampleGrid(n int, jitter bool) {
+	radius := ps.filter.Radius()
+	cell := 2 * radius / float64(n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			ox, oy := 0.5, 0.5
+			if jitter {
+				ox, oy = ps.rnd.next(), ps.rnd.next()
+			}
+			dx := -radius + (float64(i)+ox)*cell
+			dy := -radius + (float64(j)+oy)*cell
+			wt := ps.filter.Weight(dx, dy)
+			if wt == 0 {
+				continue
+			}
+			ps.add(ps.sample(ps.cx+dx, ps.cy+dy), wt)
+		}
+	}
+}
+
+// Returns the color seen through canvas point (px, py): black if it is
+// off the edge of the projection.
+func (ps *pixelSampler) sample(px, py float64) Color {
+	r, ok := ps.cam.sampleRay(px, py, ps.rnd)
+	if !ok {
+		return Black()
+	}
+	ps.stats.Samples++
+	return ps.shader.ColorAt(ps.w, r, ps.opts.MaxDepth, ps.stats)
+}
+
+func (ps *pixelSampler) add(c Color, wt float64) {
+	ps.sum = ps.sum.plus(c.scale(wt))
+	ps.weights += wt
+	ps.count++
+	l := c.luminance()
+	d := l - ps.mean
+	ps.mean += d / float64(ps.count)
+	ps.m2 += d * (l - ps.mean)
+}
+
+// Returns the filtered average of the samples, or if the filter gave
+// them no weight in total, the color at the pixel center.
+func (ps *pixelSampler) color() Color {
+	if ps.weights == 0 {
+		return ps.sample(ps.cx, ps.cy)
+	}
+	return ps.sum.scale(1 / ps.weights)
+}
+
+// Returns the standard error of the mean sample luminance.
+func (ps *pixelSampler) stdErr() float64 {
+	if ps.count < 2 {
+		return math.Inf(1)
+	}
+	variance := ps.m2 / float64(ps.count-1)
+	return math.Sqrt(variance / float64(ps.count))
+}
+
+func newRenderProgress(done, total int, elapsed time.Duration) RenderProgress {
+	p := RenderProgress{PixelsDone: done, TotalPixels: total, Elapsed: elapsed}
+	if done > 0 {
+		p.ETA = time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+	}
+	return p
+}
@@ -0,0 +1,347 @@
+package raytracer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCameraRender(t *testing.T) {
+	w := NewDefaultWorld()
+	view := MakeViewTransform(Point{0, 0, -5}, Point{0, 0, 0}, Vector{0, 1, 0})
+	c := NewCamera(11, 11, math.Pi/2, view)
+	image := c.Render(w)
+	got, want := image.Get(5, 5), Color{0.38066, 0.47583, 0.2855}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestCameraRenderWithOptionsStats(t *testing.T) {
+	w := mirrorWorld()
+	view := MakeViewTransform(Point{0, 0, -3}, Point{0, 0, 0}, Vector{0, 1, 0})
+	c := NewCamera(11, 11, math.Pi/4, view)
+	_, stats := c.RenderWithOptions(w, RenderOptions{MaxDepth: 0})
+	if stats.DepthLimitHits == 0 {
+		t.Errorf("DepthLimitHits = 0; want > 0")
+	}
+	_, stats = c.RenderWithOptions(w, DefaultRenderOptions())
+	if stats.DepthLimitHits != 0 {
+		t.Errorf("DepthLimitHits = %d; want 0", stats.DepthLimitHits)
+	}
+}
+
+func TestRenderWorkersMatchSerial(t *testing.T) {
+	w := NewDefaultWorld()
+	floor := NewPlane(MakeTranslation(0, -1, 0))
+	floor.Material().Reflective = 0.5
+	w.AddShape(floor)
+	view := MakeViewTransform(Point{0, 1, -5}, Point{0, 0, 0}, Vector{0, 1, 0})
+	c := NewCamera(40, 30, math.Pi/3, view)
+
+	opts := DefaultRenderOptions()
+	opts.Workers = 1
+	want, wantStats := c.RenderWithOptions(w, opts)
+	for _, workers := range []int{2, 7, 0} {
+		opts.Workers = workers
+		got, gotStats := c.RenderWithOptions(w, opts)
+		for y := 0; y < c.Height; y++ {
+			for x := 0; x < c.Width; x++ {
+				if got.Get(x, y) != want.Get(x, y) {
+					t.Fatalf("workers %d: pixel (%d, %d) = %v; want %v", workers, x, y, got.Get(x, y), want.Get(x, y))
+				}
+			}
+		}
+		if gotStats != wantStats {
+			t.Errorf("workers %d: stats %+v; want %+v", workers, gotStats, wantStats)
+		}
+	}
+}
+
+func defaultWorldCamera(width, height int) *Camera {
+	view := MakeViewTransform(Point{0, 0, -5}, Point{0, 0, 0}, Vector{0, 1, 0})
+	return NewCamera(width, height, math.Pi/3, view)
+}
+
+func TestRenderContextProgress(t *testing.T) {
+	c := defaultWorldCamera(10, 8)
+	var reports []RenderProgress
+	opts := DefaultRenderOptions()
+	opts.Progress = func(p RenderProgress) {
+		reports = append(reports, p)
+	}
+	_, _, err := c.RenderContext(context.Background(), NewDefaultWorld(), opts)
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	if len(reports) != c.Height {
+		t.Fatalf("got %d progress reports; want %d", len(reports), c.Height)
+	}
+	for i, p := range reports {
+		if want := (i + 1) * c.Width; p.PixelsDone != want {
+			t.Errorf("report %d: PixelsDone = %d; want %d", i, p.PixelsDone, want)
+		}
+		if p.TotalPixels != c.Width*c.Height {
+			t.Errorf("report %d: TotalPixels = %d; want %d", i, p.TotalPixels, c.Width*c.Height)
+		}
+	}
+	if last := reports[len(reports)-1]; last.ETA != 0 {
+		t.Errorf("final ETA = %v; want 0", last.ETA)
+	}
+}
+
+func TestNewRenderProgressETA(t *testing.T) {
+	got := newRenderProgress(25, 100, 10*time.Second).ETA
+	if want := 30 * time.Second; got != want {
+		t.Errorf("ETA = %v; want %v", got, want)
+	}
+}
+
+func TestRenderContextCancelled(t *testing.T) {
+	c := defaultWorldCamera(11, 11)
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := DefaultRenderOptions()
+	opts.Workers = 1
+	// Cancel once the first row is done.
+	opts.Progress = func(p RenderProgress) {
+		cancel()
+	}
+	canvas, _, err := c.RenderContext(ctx, NewDefaultWorld(), opts)
+	if err != context.Canceled {
+		t.Fatalf("got error %v; want %v", err, context.Canceled)
+	}
+	if canvas == nil {
+		t.Fatal("got nil canvas; want partial canvas")
+	}
+	// The middle of the image, where the sphere is, was never rendered.
+	if got := canvas.Get(5, 5); got != Black() {
+		t.Errorf("center pixel = %v; want %v", got, Black())
+	}
+}
+
+func TestRenderContextAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := defaultWorldCamera(10, 10).RenderContext(ctx, NewDefaultWorld(), DefaultRenderOptions())
+	if err != context.Canceled {
+		t.Errorf("got error %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestRenderSingleSampleMatchesPixelCenter(t *testing.T) {
+	c := defaultWorldCamera(11, 11)
+	w := NewDefaultWorld()
+	want := c.Render(w)
+	opts := DefaultRenderOptions()
+	opts.Samples = 1
+	opts.Filter = MitchellFilter{2, 1.0 / 3, 1.0 / 3}
+	got, _ := c.RenderWithOptions(w, opts)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			if got.Get(x, y) != want.Get(x, y) {
+				t.Fatalf("pixel (%d, %d) = %v; want %v", x, y, got.Get(x, y), want.Get(x, y))
+			}
+		}
+	}
+}
+
+// Returns a 4×4 camera and a world in which a thin unlit white box covers
+// the view from a quarter of the way into pixel column 2, so that column
+// sees both it and the black background, while column 3 sees only the
+// box.
+func edgeWorldCamera() (World, *Camera) {
+	w := NewEmptyWorld()
+	box := NewCube(MakeScaling(10, 10, 0.01).Translate(-10.5, 0, -4.01))
+	box.Material().Ambient = 1
+	box.Material().Diffuse = 0
+	box.Material().Specular = 0
+	w.AddShape(box)
+	w.AddLight(NewPointLight(Point{0, 0, 0}, White()))
+	return w, NewCamera(4, 4, math.Pi/2, MakeIdentity())
+}
+
+func TestRenderSupersampledEdge(t *testing.T) {
+	w, c := edgeWorldCamera()
+
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want float64
+	}{
+		{"single sample", RenderOptions{}, 1},
+		{"stratified box", RenderOptions{Samples: 4}, 0.75},
+		// Samples at 0.4 pixel spacing from -0.8 to 0.8; the last three
+		// (weights 1, 0.6, 0.2 of 2.6) hit the box.
+		{"stratified tent", RenderOptions{Samples: 5, Filter: TentFilter{1}}, 1.8 / 2.6},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canvas, _ := c.RenderWithOptions(w, test.opts)
+			got := canvas.Get(2, 1).R
+			if !approxEq(got, test.want) {
+				t.Error(approxError(got, test.want))
+			}
+		})
+	}
+}
+
+// Gives no weight to any sample.
+type zeroFilter struct{}
+
+func (zeroFilter) Radius() float64 {
+	return 0.5
+}
+
+func (zeroFilter) Weight(dx, dy float64) float64 {
+	return 0
+}
+
+func TestRenderZeroValueAndZeroWeightFilters(t *testing.T) {
+	c := defaultWorldCamera(11, 11)
+	w := NewDefaultWorld()
+	tests := []struct {
+		name        string
+		filter      Filter
+		wantSamples int
+	}{
+		{"mitchell zero value", MitchellFilter{}, 11 * 11 * 4},
+		// Falls back to one sample at each pixel center.
+		{"no weight", zeroFilter{}, 11 * 11},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, stats := c.RenderWithOptions(w, RenderOptions{Samples: 2, Filter: test.filter})
+			if stats.Samples != test.wantSamples {
+				t.Errorf("Samples = %d; want %d", stats.Samples, test.wantSamples)
+			}
+			// The middle of the view is on a sphere, so not black.
+			if mid := got.Get(5, 5); mid.luminance() < 0.1 {
+				t.Errorf("center pixel = %v; want the sphere", mid)
+			}
+		})
+	}
+}
+
+func TestRenderJitteredRepeatable(t *testing.T) {
+	c := defaultWorldCamera(20, 15)
+	w := NewDefaultWorld()
+	opts := DefaultRenderOptions()
+	opts.Samples = 3
+	opts.Sampling = Jittered
+	opts.Filter = GaussianFilter{1, 2}
+	opts.Seed = 7
+	opts.Workers = 1
+	want, _ := c.RenderWithOptions(w, opts)
+	opts.Workers = 4
+	got, _ := c.RenderWithOptions(w, opts)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			if got.Get(x, y) != want.Get(x, y) {
+				t.Fatalf("pixel (%d, %d) = %v; want %v", x, y, got.Get(x, y), want.Get(x, y))
+			}
+		}
+	}
+
+	opts.Seed = 8
+	other, _ := c.RenderWithOptions(w, opts)
+	same := true
+	for y := 0; y < c.Height && same; y++ {
+		for x := 0; x < c.Width; x++ {
+			if other.Get(x, y) != want.Get(x, y) {
+				same = false
+				break
+			}
+		}
+	}
+	if same {
+		t.Error("render with a different seed was identical")
+	}
+}
+
+func TestRenderAdaptive(t *testing.T) {
+	w, c := edgeWorldCamera()
+	opts := RenderOptions{Samples: 4, AdaptiveThreshold: 0.01, MaxSamples: 64}
+	image, heatmap, stats, err := c.RenderWithHeatmap(context.Background(), w, opts)
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	tests := []struct {
+		name        string
+		x, y        int
+		wantColor   float64
+		wantHeatmap float64
+	}{
+		{"background", 0, 1, 0, 0.25},
+		{"box", 3, 1, 1, 0.25},
+		{"edge", 2, 1, 0.75, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := heatmap.Get(test.x, test.y).R; !approxEq(got, test.wantHeatmap) {
+				t.Errorf("heatmap: %s", approxError(got, test.wantHeatmap))
+			}
+			// Jittered samples only approximate the edge pixel's coverage.
+			if got := image.Get(test.x, test.y).R; math.Abs(got-test.wantColor) > 0.1 {
+				t.Errorf("color = %v; want %v", got, test.wantColor)
+			}
+		})
+	}
+	// 4 edge pixels at 64 samples; 12 flat ones at 16.
+	if got, want := stats.Samples, 4*64+12*16; got != want {
+		t.Errorf("Samples = %d; want %d", got, want)
+	}
+}
+
+func TestRenderAdaptiveGridOverMaxSamples(t *testing.T) {
+	w, c := edgeWorldCamera()
+	tests := []struct {
+		name string
+		opts RenderOptions
+		// Range of the total samples over all 16 pixels.
+		minSamples, maxSamples int
+	}{
+		// The 5×5 grid is cut to 3×3, with no room for a second.
+		{"grid cut", RenderOptions{Samples: 5, AdaptiveThreshold: 0.01, MaxSamples: 10}, 16 * 9, 16 * 9},
+		// Single samples, stopping once 2 agree or at 3.
+		{"below 2x2", RenderOptions{Samples: 2, AdaptiveThreshold: 0.01, MaxSamples: 3}, 16 * 2, 16 * 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, heatmap, stats, err := c.RenderWithHeatmap(context.Background(), w, test.opts)
+			if err != nil {
+				t.Fatalf("got error %v; want nil", err)
+			}
+			for y := 0; y < c.Height; y++ {
+				for x := 0; x < c.Width; x++ {
+					if got := heatmap.Get(x, y).R; got > 1 {
+						t.Errorf("heatmap at (%d, %d) = %v; want at most 1", x, y, got)
+					}
+				}
+			}
+			if stats.Samples < test.minSamples || stats.Samples > test.maxSamples {
+				t.Errorf("Samples = %d; want in [%d, %d]", stats.Samples, test.minSamples, test.maxSamples)
+			}
+		})
+	}
+}
+
+func TestRenderStatsSamples(t *testing.T) {
+	c := defaultWorldCamera(10, 8)
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want int
+	}{
+		{"single", RenderOptions{}, 80},
+		{"supersampled", RenderOptions{Samples: 3}, 80 * 9},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, stats := c.RenderWithOptions(NewDefaultWorld(), test.opts)
+			if stats.Samples != test.want {
+				t.Errorf("Samples = %d; want %d", stats.Samples, test.want)
+			}
+		})
+	}
+}
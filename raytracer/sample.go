@@ -0,0 +1,90 @@
+package raytracer
+
+import "math"
+
+// How supersamples are placed within the area a pixel samples.
+type SamplingPattern int
+
+const (
+	// Samples at the centers of an N×N grid of cells.
+	Stratified SamplingPattern = iota
+	// Samples at a random point within each cell of an N×N grid.
+	Jittered
+)
+
+// Maps (u, v) in the unit square onto the unit disk, keeping uniformly
+// spread points uniform and nearby points near (Shirley and Chiu's
+// concentric mapping).
+func sampleDisk(u, v float64) (x, y float64) {
+	a, b := 2*u-1, 2*v-1
+	if a == 0 && b == 0 {
+		return 0, 0
+	}
+	var r, theta float64
+	if math.Abs(a) > math.Abs(b) {
+		r, theta = a, math.Pi/4*(b/a)
+	} else {
+		r, theta = b, math.Pi/2-math.Pi/4*(a/b)
+	}
+	return r * math.Cos(theta), r * math.Sin(theta)
+}
+
+// Maps (u, v) in the unit square onto unit vectors spread evenly over
+// the sphere.
+func sampleSphere(u, v float64) Vector {
+	z := 1 - 2*u
+	r := math.Sqrt(math.Max(0, 1-z*z))
+	phi := 2 * math.Pi * v
+	return Vector{r * math.Cos(phi), r * math.Sin(phi), z}
+}
+
+// Maps (u, v) in the unit square onto unit vectors in the hemisphere
+// around unit normal n, spread in proportion to their cosine with n, so
+// with density cos/π per unit solid angle.
+func sampleHemisphere(u, v float64, n Vector) Vector {
+	x, y := sampleDisk(u, v)
+	z := math.Sqrt(math.Max(0, 1-x*x-y*y))
+	a, b := perpendiculars(n)
+	return a.Scale(x).Plus(b.Scale(y)).Plus(n.Scale(z))
+}
+
+// sampleRand is a small, fast pseudo-random generator (splitmix64)
+// seeded per pixel, shaded point or ray, so renders repeat exactly
+// whatever order pixels are rendered in.
+type sampleRand struct {
+	state uint64
+}
+
+func newPixelRand(seed int64, x, y int) *sampleRand {
+	r := &sampleRand{uint64(seed)}
+	r.state ^= r.mix(uint64(x)<<32 | uint64(uint32(y)))
+	return r
+}
+
+func newPointRand(seed int64, p Point) *sampleRand {
+	r := &sampleRand{uint64(seed)}
+	for _, c := range []float64{p.X, p.Y, p.Z} {
+		r.state = r.mix(r.state ^ math.Float64bits(c))
+	}
+	return r
+}
+
+func newRayRand(seed int64, ray Ray) *sampleRand {
+	r := newPointRand(seed, ray.orig)
+	for _, c := range []float64{ray.dir.X, ray.dir.Y, ray.dir.Z, ray.time} {
+		r.state = r.mix(r.state ^ math.Float64bits(c))
+	}
+	return r
+}
+
+func (r *sampleRand) mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Returns a pseudo-random number in [0, 1).
+func (r *sampleRand) next() float64 {
+	r.state += 0x9e3779b97f4a7c15
+	return float64(r.mix(r.state)>>11) / (1 << 53)
+}
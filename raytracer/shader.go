@@ -1,37 +1,134 @@
 package raytracer
 
+import "math"
+
+// A Shader computes the color seen along a ray cast into a World.
+// depth is the number of further secondary rays (reflected or
+// refracted) the shader may trace from r; stats accumulates counts
+// for the render.
 type Shader interface {
-	ColorAt(s Shape, r Ray) Color
+	ColorAt(w World, r Ray, depth int, stats *RenderStats) Color
 }
 
+// PrimitiveShader colors every hit red, for silhouette renders.
 type PrimitiveShader struct{}
 
-func (ps PrimitiveShader) ColorAt(s Shape, r Ray) Color {
-  mxs := s.Intersect(r)
-  xs := Intersections(mxs)
+func (ps PrimitiveShader) ColorAt(w World, r Ray, depth int, stats *RenderStats) Color {
+	xs := Intersections(w.Intersect(r))
 	if hit(xs) != nil {
 		return Red()
 	}
 	return Black()
 }
 
+// lightShader applies Phong lighting from every light in the World,
+// plus reflections and refractions. Rays that miss everything see the
+// World's background.
 type lightShader struct {
-	L Light
+	// Number of directions sampled to light each hit by the background;
+	// 0 for none.
+	envSamples int
+	// Seeds the choice of directions.
+	seed int64
 }
 
 func NewLightShader() Shader {
-	l := NewPointLight(Point{-10, 10, -10}, White())
-	return lightShader{l}
+	return lightShader{}
+}
+
+// NewEnvironmentShader returns a shader that lights surfaces as
+// NewLightShader's does, and also by the World's background, as seen from
+// each hit. That light is estimated from the given number of directions,
+// chosen by importance sampling the background; they are random, but
+// the same for the same seed and hit. Materials' Ambient stands in for
+// light from the surroundings, so is usually 0 with this shader.
+func NewEnvironmentShader(samples int, seed int64) Shader {
+	return lightShader{samples, seed}
 }
 
-func (ls lightShader) ColorAt(s Shape, r Ray) Color {
-  mxs := s.Intersect(r)
-  xs := Intersections(mxs)
+func (ls lightShader) ColorAt(w World, r Ray, depth int, stats *RenderStats) Color {
+	xs := Intersections(w.Intersect(r))
 	x := hit(xs)
 	if x == nil {
+		return w.Background().Radiance(r.dir)
+	}
+	return ls.shadeHit(w, x, depth, stats)
+}
+
+// Returns the color at the given precomputed intersection: the light
+// it gives off, plus that from all lights in the world.
+func (ls lightShader) shadeHit(w World, x *Intersection, depth int, stats *RenderStats) Color {
+	c := x.material.Emissive
+	for _, l := range w.Lights() {
+		visibility := w.LightVisibility(x.overPoint, l, x.time)
+		c = c.plus(Lighting(x.material, l, x.overPoint, x.eyeV, x.normalV, visibility))
+	}
+	if ls.envSamples > 0 {
+		c = c.plus(ls.environmentColor(w, x))
+	}
+	reflected := ls.reflectedColor(w, x, depth, stats)
+	refracted := ls.refractedColor(w, x, depth, stats)
+	if x.material.Reflective > 0 && x.material.Transparency > 0 {
+		reflectance := x.schlick()
+		return c.plus(reflected.scale(reflectance)).plus(refracted.scale(1 - reflectance))
+	}
+	return c.plus(reflected).plus(refracted)
+}
+
+// Returns the color contributed by mirror reflection at x.
+func (ls lightShader) reflectedColor(w World, x *Intersection, depth int, stats *RenderStats) Color {
+	if x.material.Reflective == 0 {
 		return Black()
 	}
+	if depth <= 0 {
+		stats.DepthLimitHits++
+		return Black()
+	}
+	r := Ray{x.overPoint, x.reflectV, x.time}
+	return ls.ColorAt(w, r, depth-1, stats).scale(x.material.Reflective)
+}
 
-	point := r.position(x.t)
-	return Lighting(x.material, ls.L, point, r.dir.Negate(), s.NormalAt(point))
+// Returns the color contributed by light refracted through x.
+func (ls lightShader) refractedColor(w World, x *Intersection, depth int, stats *RenderStats) Color {
+	if x.material.Transparency == 0 {
+		return Black()
+	}
+	dir, ok := x.refractV()
+	if !ok {
+		// Total internal reflection.
+		return Black()
+	}
+	if depth <= 0 {
+		stats.DepthLimitHits++
+		return Black()
+	}
+	r := Ray{x.underPoint, dir, x.time}
+	return ls.ColorAt(w, r, depth-1, stats).scale(x.material.Transparency)
+}
+
+// Returns the diffuse and specular light at x from the World's
+// background, by Monte Carlo integration over directions sampled from
+// the background.
+func (ls lightShader) environmentColor(w World, x *Intersection) Color {
+	bg := w.Background()
+	m := x.material
+	diffuse := m.Color.scale(m.Diffuse / math.Pi)
+	// Normalized so the Phong lobe reflects no more light than it gets.
+	specularNorm := m.Specular * (m.Shininess + 2) / (2 * math.Pi)
+	rnd := newPointRand(ls.seed, x.overPoint)
+	sum := Black()
+	for i := 0; i < ls.envSamples; i++ {
+		dir, pdf := bg.Sample(rnd.next(), rnd.next())
+		cos := dir.Dot(x.normalV)
+		if cos <= 0 || pdf <= 0 || occluded(w, Ray{x.overPoint, dir, x.time}, math.Inf(1)) {
+			continue
+		}
+		f := diffuse
+		if reflectDotEye := dir.Negate().Reflect(x.normalV).Dot(x.eyeV); reflectDotEye > 0 {
+			s := specularNorm * math.Pow(reflectDotEye, m.Shininess)
+			f = f.plus(Color{s, s, s})
+		}
+		sum = sum.plus(bg.Radiance(dir).times(f).scale(cos / pdf))
+	}
+	return sum.scale(1 / float64(ls.envSamples))
 }
@@ -0,0 +1,291 @@
+package raytracer
+
+import (
+	"math"
+	"testing"
+)
+
+// Sets m to show only the ambient color c.
+func ambientMaterial(m *Material, c Color) {
+	m.Color = c
+	m.Ambient = 1
+	m.Diffuse = 0
+	m.Specular = 0
+}
+
+// Returns a world with a perfect mirror sphere at the origin and a red
+// sphere behind a ray travelling +z from (0, 0, -3).
+func mirrorWorld() World {
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{-10, 10, -10}, White()))
+	mirror := NewSphere(MakeIdentity())
+	ambientMaterial(mirror.Material(), Black())
+	mirror.Material().Reflective = 1
+	w.AddShape(mirror)
+	target := NewSphere(MakeTranslation(0, 0, -10))
+	ambientMaterial(target.Material(), Red())
+	w.AddShape(target)
+	return w
+}
+
+func TestLightShaderReflection(t *testing.T) {
+	tests := []struct {
+		name          string
+		reflective    float64
+		depth         int
+		want          Color
+		wantLimitHits int
+	}{
+		{
+			name:       "nonreflective material",
+			reflective: 0,
+			depth:      DefaultMaxDepth,
+			want:       Black(),
+		},
+		{
+			name:       "reflective material",
+			reflective: 1,
+			depth:      DefaultMaxDepth,
+			want:       Red(),
+		},
+		{
+			name:       "partially reflective material",
+			reflective: 0.5,
+			depth:      DefaultMaxDepth,
+			want:       Color{0.5, 0, 0},
+		},
+		{
+			name:          "at maximum recursive depth",
+			reflective:    1,
+			depth:         0,
+			want:          Black(),
+			wantLimitHits: 1,
+		},
+	}
+	r := Ray{Point{0, 0, -3}, Vector{0, 0, 1}, 0}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := mirrorWorld()
+			w.Shapes()[0].Material().Reflective = test.reflective
+			stats := RenderStats{}
+			got, want := NewLightShader().ColorAt(w, r, test.depth, &stats), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+			if stats.DepthLimitHits != test.wantLimitHits {
+				t.Errorf("DepthLimitHits = %d; want %d", stats.DepthLimitHits, test.wantLimitHits)
+			}
+		})
+	}
+}
+
+func TestLightShaderMutuallyReflectiveSurfaces(t *testing.T) {
+	// A ray inside a mirror sphere bounces back and forth forever.
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{0, 0, 0}, White()))
+	s := NewSphere(MakeIdentity())
+	s.Material().Reflective = 1
+	w.AddShape(s)
+	r := Ray{Point{0, 0, 0}, Vector{0, 1, 0}, 0}
+
+	stats := RenderStats{}
+	NewLightShader().ColorAt(w, r, 3, &stats)
+	if stats.DepthLimitHits != 1 {
+		t.Errorf("DepthLimitHits = %d; want 1", stats.DepthLimitHits)
+	}
+}
+
+// Returns a world with a glass sphere at the origin and a red sphere
+// beyond it from a ray travelling +z from (0, 0, -3).
+func glassWorld(transparency, refractiveIndex float64) World {
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{-10, 10, -10}, White()))
+	glass := NewSphere(MakeIdentity())
+	ambientMaterial(glass.Material(), Black())
+	glass.Material().Transparency = transparency
+	glass.Material().RefractiveIndex = refractiveIndex
+	w.AddShape(glass)
+	target := NewSphere(MakeTranslation(0, 0, 10))
+	ambientMaterial(target.Material(), Red())
+	w.AddShape(target)
+	return w
+}
+
+func TestLightShaderRefraction(t *testing.T) {
+	tests := []struct {
+		name            string
+		transparency    float64
+		refractiveIndex float64
+		depth           int
+		r               Ray
+		want            Color
+		wantLimitHits   int
+	}{
+		{
+			name:            "opaque surface",
+			transparency:    0,
+			refractiveIndex: 1.5,
+			depth:           DefaultMaxDepth,
+			r:               Ray{Point{0, 0, -3}, Vector{0, 0, 1}, 0},
+			want:            Black(),
+		},
+		{
+			name:            "transparent surface",
+			transparency:    0.5,
+			refractiveIndex: 1.0,
+			depth:           DefaultMaxDepth,
+			r:               Ray{Point{0, 0, -3}, Vector{0, 0, 1}, 0},
+			want:            Color{0.25, 0, 0},
+		},
+		{
+			name:            "at maximum recursive depth",
+			transparency:    1,
+			refractiveIndex: 1.5,
+			depth:           0,
+			r:               Ray{Point{0, 0, -3}, Vector{0, 0, 1}, 0},
+			want:            Black(),
+			wantLimitHits:   1,
+		},
+		{
+			name:            "under total internal reflection",
+			transparency:    1,
+			refractiveIndex: 1.5,
+			depth:           DefaultMaxDepth,
+			r:               Ray{Point{0, 0, math.Sqrt(2) / 2}, Vector{0, 1, 0}, 0},
+			want:            Black(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := glassWorld(test.transparency, test.refractiveIndex)
+			stats := RenderStats{}
+			got, want := NewLightShader().ColorAt(w, test.r, test.depth, &stats), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+			if stats.DepthLimitHits != test.wantLimitHits {
+				t.Errorf("DepthLimitHits = %d; want %d", stats.DepthLimitHits, test.wantLimitHits)
+			}
+		})
+	}
+}
+
+func TestLightShaderOverPlane(t *testing.T) {
+	tests := []struct {
+		name         string
+		reflective   float64
+		transparency float64
+		want         Color
+	}{
+		{
+			name:       "reflective floor",
+			reflective: 0.5,
+			want:       Color{0.87677, 0.92436, 0.82918},
+		},
+		{
+			name:         "transparent floor",
+			transparency: 0.5,
+			want:         Color{0.93642, 0.68642, 0.68642},
+		},
+		{
+			name:         "reflective, transparent floor",
+			reflective:   0.5,
+			transparency: 0.5,
+			want:         Color{0.93391, 0.69643, 0.69243},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewDefaultWorld()
+			floor := NewPlane(MakeTranslation(0, -1, 0))
+			floor.Material().Reflective = test.reflective
+			floor.Material().Transparency = test.transparency
+			floor.Material().RefractiveIndex = 1.5
+			w.AddShape(floor)
+			if test.transparency > 0 {
+				ball := NewSphere(MakeTranslation(0, -3.5, -0.5))
+				ball.Material().Color = Red()
+				ball.Material().Ambient = 0.5
+				w.AddShape(ball)
+			}
+			r := Ray{Point{0, 0, -3}, Vector{0, -math.Sqrt(2) / 2, math.Sqrt(2) / 2}, 0}
+			got, want := w.ColorAt(r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestLightShaderMissSeesBackground(t *testing.T) {
+	w := NewEmptyWorld()
+	w.SetBackground(GradientBackground{Black(), White()})
+	r := Ray{Point{0, 0, 0}, Vector{0, 1, 0}, 0}
+	got, want := NewLightShader().ColorAt(w, r, DefaultMaxDepth, &RenderStats{}), White()
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestEnvironmentShader(t *testing.T) {
+	upperHalf := MakeCanvas(16, 8)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 16; x++ {
+			upperHalf.Set(x, y, White())
+		}
+	}
+	white := MakeCanvas(16, 8)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			white.Set(x, y, White())
+		}
+	}
+	tests := []struct {
+		name    string
+		b       Background
+		ceiling bool
+		// Irradiance at the floor, as a fraction of that under a uniform
+		// white sky.
+		want float64
+	}{
+		{name: "white sky", b: SolidBackground{White()}, want: 1},
+		{name: "white environment map", b: NewEnvironmentMap(white), want: 1},
+		{name: "white upper hemisphere", b: NewEnvironmentMap(upperHalf), want: 1},
+		{name: "gradient sky", b: GradientBackground{Black(), White()}, want: 5.0 / 6},
+		{name: "under a ceiling", b: SolidBackground{White()}, ceiling: true, want: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewEmptyWorld()
+			w.SetBackground(test.b)
+			floor := NewPlane(MakeIdentity())
+			floor.Material().Ambient = 0
+			floor.Material().Diffuse = 0.8
+			floor.Material().Specular = 0
+			w.AddShape(floor)
+			if test.ceiling {
+				w.AddShape(NewPlane(MakeTranslation(0, 2, 0)))
+			}
+			r := Ray{Point{0, 1, 0}, Vector{0, -1, 0}, 0}
+			got := NewEnvironmentShader(2000, 1).ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+			want := 0.8 * test.want
+			if math.Abs(got.R-want) > 0.03 || got.G != got.R || got.B != got.R {
+				t.Errorf("got %v; want gray %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLightShaderEmissive(t *testing.T) {
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{-10, 10, -10}, White()))
+	s := NewSphere(MakeIdentity())
+	ambientMaterial(s.Material(), Color{0.1, 0.1, 0.1})
+	s.Material().Emissive = Color{0.5, 0, 0}
+	w.AddShape(s)
+	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+	got, want := NewLightShader().ColorAt(w, r, DefaultMaxDepth, &RenderStats{}), Color{0.6, 0.1, 0.1}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
@@ -5,42 +5,151 @@ import (
 )
 
 type Shape interface {
+	// Returns the intersections of r, given in the space of the shape's
+	// parent, with the shape. Normals are in world space.
 	Intersect(r Ray) []MaterialIntersection
 	Xform() *Matrix
+	// Returns the world-space normal at the given world-space point, at
+	// time 0. Groups and CSG shapes have no surface of their own, so
+	// return the zero vector.
 	NormalAt(p Point) Vector
+	// Returns the material the shape is drawn with. The material of a
+	// Group or CSG shape is unused, as its children carry their own.
 	Material() *Material
+	// Returns a box containing the shape, in the space of its parent.
+	Bounds() Bounds
+	// Returns the Group or CSG shape containing this shape, or nil.
+	Parent() Shape
+	// Makes the shape move, replacing its transform by one interpolated
+	// between the keyframes at the time of each ray. Call it before
+	// adding the shape to a Group or CSG shape, as they cache its bounds.
+	// Does nothing if there are no keyframes.
+	Animate(keyframes ...Keyframe)
+	setParent(p Shape)
+	worldToObject(p Point, time float64) Point
+	normalToWorld(n Vector, time float64) Vector
 }
 
-type sphere struct {
+// shapeBase holds the state common to all shapes: the transform from
+// object space to parent space, the material and the parent shape.
+type shapeBase struct {
 	xf       *Matrix
 	ixf      *Matrix
 	tixf     *Matrix
 	material *Material
+	parent   Shape
+	// Whether xf is the identity, so conversions can be skipped.
+	identity bool
+	// Set if the shape moves, so its transform depends on time; xf is
+	// then its transform at the first keyframe.
+	motion *motion
 }
 
-func NewSphere(m *Matrix) Shape {
+func newShapeBase(m *Matrix) shapeBase {
 	xf := m.Copy()
 	ixf := xf.inverse()
 	tixf := ixf.transpose()
-	material := NewMaterial()
-	return &sphere{xf, ixf, tixf, material}
+	return shapeBase{xf, ixf, tixf, NewMaterial(), nil, xf.isIdentity(), nil}
+}
+
+func (b *shapeBase) Xform() *Matrix {
+	return b.xf
+}
+
+func (b *shapeBase) Material() *Material {
+	return b.material
+}
+
+func (b *shapeBase) Parent() Shape {
+	return b.parent
+}
+
+func (b *shapeBase) setParent(p Shape) {
+	b.parent = p
+}
+
+func (b *shapeBase) Animate(keyframes ...Keyframe) {
+	if len(keyframes) == 0 {
+		return
+	}
+	b.motion = newMotion(keyframes)
+	b.xf = b.motion.keyframes[0].Xform
+	b.ixf = b.motion.inverses[0]
+	b.tixf = b.ixf.transpose()
+	b.identity = false
+}
+
+// Returns the inverse of the shape's transform at the given time.
+func (b *shapeBase) ixfAt(time float64) *Matrix {
+	if b.motion == nil {
+		return b.ixf
+	}
+	_, ixf := b.motion.at(time)
+	return ixf
+}
+
+// Converts a world-space point into this shape's object space at the
+// given time, through the transforms of all enclosing shapes.
+func (b *shapeBase) worldToObject(p Point, time float64) Point {
+	if b.parent != nil {
+		p = b.parent.worldToObject(p, time)
+	}
+	if b.identity {
+		return p
+	}
+	return b.ixfAt(time).TimesP(p)
+}
+
+// Converts an object-space normal into world space at the given time,
+// through the transforms of all enclosing shapes.
+func (b *shapeBase) normalToWorld(n Vector, time float64) Vector {
+	switch {
+	case b.motion != nil:
+		n = b.ixfAt(time).transpose().TimesV(n)
+	case !b.identity:
+		n = b.tixf.TimesV(n)
+	}
+	n = n.Norm()
+	if b.parent != nil {
+		n = b.parent.normalToWorld(n, time)
+	}
+	return n
+}
+
+// Converts object-space bounds into parent space, covering any motion.
+func (b *shapeBase) parentBounds(objectBounds Bounds) Bounds {
+	switch {
+	case b.motion != nil:
+		return b.motion.bounds(objectBounds)
+	case b.identity:
+		return objectBounds
+	}
+	return objectBounds.xform(b.xf)
+}
+
+type sphere struct {
+	shapeBase
+}
+
+func NewSphere(m *Matrix) Shape {
+	return &sphere{newShapeBase(m)}
 }
 
 func (s *sphere) Intersect(r Ray) []MaterialIntersection {
-  var xs []MaterialIntersection
-  for _, t := range s.intersectPoints(r) {
-    p := r.position(t)
-    normalV := s.NormalAt(p)
-    xs := append(xs, NewMaterialIntersection(r, t, normalV, s.material))
-  }
-  return xs
+	xs := []MaterialIntersection{}
+	xfray := r.xform(s.ixfAt(r.time))
+	for _, t := range s.intersectPoints(xfray) {
+		normalV := s.normalToWorld(s.localNormalAt(xfray.position(t)), r.time)
+		xs = append(xs, NewMaterialIntersection(r, t, normalV, s.material))
+	}
+	return xs
 }
 
+// Returns the t values where the object-space ray crosses the sphere.
 func (s *sphere) intersectPoints(r Ray) []float64 {
-	xfray := r.xform(s.xf.inverse())
-	sphereToRay := xfray.orig.Minus(Point{0, 0, 0})
-	a := xfray.dir.Dot(xfray.dir)
-	b := 2.0 * xfray.dir.Dot(sphereToRay)
+	sphereToRay := r.orig.Minus(Point{0, 0, 0})
+	a := r.dir.Dot(r.dir)
+	b := 2.0 * r.dir.Dot(sphereToRay)
 	c := sphereToRay.Dot(sphereToRay) - 1
 	disc := b*b - 4*a*c
 	if disc < 0 {
@@ -51,17 +160,14 @@ func (s *sphere) intersectPoints(r Ray) []float64 {
 	return []float64{t1, t2}
 }
 
-func (s *sphere) Xform() *Matrix {
-	return s.xf
+func (s *sphere) Bounds() Bounds {
+	return s.parentBounds(Bounds{Point{-1, -1, -1}, Point{1, 1, 1}})
 }
 
-func (s *sphere) Material() *Material {
-	return s.material
+func (s *sphere) NormalAt(worldPoint Point) Vector {
+	return s.normalToWorld(s.localNormalAt(s.worldToObject(worldPoint, 0)), 0)
 }
 
-func (s *sphere) NormalAt(worldPoint Point) Vector {
-	objectPoint := s.ixf.TimesP(worldPoint)
-	objectNormal := Vector{objectPoint.X, objectPoint.Y, objectPoint.Z}
-	worldNormal := s.tixf.TimesV(objectNormal)
-	return worldNormal.Norm()
+func (s *sphere) localNormalAt(objectPoint Point) Vector {
+	return Vector{objectPoint.X, objectPoint.Y, objectPoint.Z}
 }
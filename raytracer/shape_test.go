@@ -28,43 +28,43 @@ func TestSphereIntersection(t *testing.T) {
 	tests := []struct {
 		name string
 		r    Ray
-		want []Intersection
+		want []MaterialIntersection
 	}{
 		{
 			name: "ray hits sphere",
-			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}},
-			want: []Intersection{
-				NewIntersection(4, s),
-				NewIntersection(6, s),
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{
+				MaterialIntersection{t: 4},
+				MaterialIntersection{t: 6},
 			},
 		},
 		{
 			name: "ray tangent to sphere",
-			r:    Ray{Point{0, 1, -5}, Vector{0, 0, 1}},
-			want: []Intersection{
-				NewIntersection(5, s),
-				NewIntersection(5, s),
+			r:    Ray{Point{0, 1, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{
+				MaterialIntersection{t: 5},
+				MaterialIntersection{t: 5},
 			},
 		},
 		{
 			name: "ray misses sphere",
-			r:    Ray{Point{0, 2, -5}, Vector{0, 0, 1}},
-			want: []Intersection{},
+			r:    Ray{Point{0, 2, -5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
 		},
 		{
 			name: "ray inside sphere",
-			r:    Ray{Point{0, 0, 0}, Vector{0, 0, 1}},
-			want: []Intersection{
-				NewIntersection(-1, s),
-				NewIntersection(1, s),
+			r:    Ray{Point{0, 0, 0}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{
+				MaterialIntersection{t: -1},
+				MaterialIntersection{t: 1},
 			},
 		},
 		{
 			name: "sphere behind ray",
-			r:    Ray{Point{0, 0, 5}, Vector{0, 0, 1}},
-			want: []Intersection{
-				NewIntersection(-6, s),
-				NewIntersection(-4, s),
+			r:    Ray{Point{0, 0, 5}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{
+				MaterialIntersection{t: -6},
+				MaterialIntersection{t: -4},
 			},
 		},
 	}
@@ -84,22 +84,22 @@ func TestTransformedSphereIntersection(t *testing.T) {
 		name string
 		r    Ray
 		xf   *Matrix
-		want []Intersection
+		want []MaterialIntersection
 	}{
 		{
 			name: "scaled sphere",
-			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}},
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
 			xf:   MakeScaling(2, 2, 2),
-			want: []Intersection{
-				NewIntersection(3, nil),
-				NewIntersection(7, nil),
+			want: []MaterialIntersection{
+				MaterialIntersection{t: 3},
+				MaterialIntersection{t: 7},
 			},
 		},
 		{
 			name: "translated sphere",
-			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}},
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
 			xf:   MakeTranslation(5, 0, 0),
-			want: []Intersection{},
+			want: []MaterialIntersection{},
 		},
 	}
 
@@ -115,7 +115,6 @@ func TestTransformedSphereIntersection(t *testing.T) {
 }
 
 func TestHit(t *testing.T) {
-	s := NewSphere(MakeIdentity())
 	tests := []struct {
 		name string
 		xs   []Intersection
@@ -124,36 +123,36 @@ func TestHit(t *testing.T) {
 		{
 			name: "all positive",
 			xs: []Intersection{
-				NewIntersection(1, s),
-				NewIntersection(2, s),
+				Intersection{t: 1},
+				Intersection{t: 2},
 			},
-			want: NewIntersectionP(1, s),
+			want: &Intersection{t: 1},
 		},
 		{
 			name: "some positive, some negative",
 			xs: []Intersection{
-				NewIntersection(-1, s),
-				NewIntersection(1, s),
+				Intersection{t: -1},
+				Intersection{t: 1},
 			},
-			want: NewIntersectionP(1, s),
+			want: &Intersection{t: 1},
 		},
 		{
 			name: "all negative",
 			xs: []Intersection{
-				NewIntersection(-2, s),
-				NewIntersection(-1, s),
+				Intersection{t: -2},
+				Intersection{t: -1},
 			},
 			want: nil,
 		},
 		{
 			name: "lowest non-negative",
 			xs: []Intersection{
-				NewIntersection(5, s),
-				NewIntersection(7, s),
-				NewIntersection(-3, s),
-				NewIntersection(2, s),
+				Intersection{t: 5},
+				Intersection{t: 7},
+				Intersection{t: -3},
+				Intersection{t: 2},
 			},
-			want: NewIntersectionP(2, s),
+			want: &Intersection{t: 2},
 		},
 	}
 	for _, test := range tests {
@@ -219,3 +218,99 @@ func TestSphereNormalAt(t *testing.T) {
 		})
 	}
 }
+
+func TestIntersectionOverPoint(t *testing.T) {
+	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+	s := NewSphere(MakeTranslation(0, 0, 1))
+	x := NewIntersection(s.Intersect(r)[0])
+	if x.overPoint.Z >= -epsilon/2 {
+		t.Errorf("overPoint.Z = %f; want < %f", x.overPoint.Z, -epsilon/2)
+	}
+	if x.point.Z <= x.overPoint.Z {
+		t.Errorf("point.Z = %f; want > overPoint.Z %f", x.point.Z, x.overPoint.Z)
+	}
+}
+
+func glassSphere(xf *Matrix, refractiveIndex float64) Shape {
+	s := NewSphere(xf)
+	s.Material().Transparency = 1
+	s.Material().RefractiveIndex = refractiveIndex
+	return s
+}
+
+func TestIntersectionsRefractiveIndices(t *testing.T) {
+	w := NewEmptyWorld()
+	w.AddShape(glassSphere(MakeScaling(2, 2, 2), 1.5))
+	w.AddShape(glassSphere(MakeTranslation(0, 0, -0.25), 2.0))
+	w.AddShape(glassSphere(MakeTranslation(0, 0, 0.25), 2.5))
+	r := Ray{Point{0, 0, -4}, Vector{0, 0, 1}, 0}
+	xs := Intersections(w.Intersect(r))
+
+	want := [][2]float64{
+		{1.0, 1.5},
+		{1.5, 2.0},
+		{2.0, 2.5},
+		{2.5, 2.5},
+		{2.5, 1.5},
+		{1.5, 1.0},
+	}
+	if len(xs) != len(want) {
+		t.Fatalf("got %d intersections; want %d", len(xs), len(want))
+	}
+	for i, x := range xs {
+		got := [2]float64{x.n1, x.n2}
+		if !approxEq(got, want[i]) {
+			t.Errorf("intersection %d: %s", i, approxError(got, want[i]))
+		}
+	}
+}
+
+func TestIntersectionUnderPoint(t *testing.T) {
+	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
+	s := glassSphere(MakeTranslation(0, 0, 1), 1.5)
+	x := Intersections(s.Intersect(r))[0]
+	if x.underPoint.Z <= epsilon/2 {
+		t.Errorf("underPoint.Z = %f; want > %f", x.underPoint.Z, epsilon/2)
+	}
+	if x.point.Z >= x.underPoint.Z {
+		t.Errorf("point.Z = %f; want < underPoint.Z %f", x.point.Z, x.underPoint.Z)
+	}
+}
+
+func TestIntersectionSchlick(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		hit  int
+		want float64
+	}{
+		{
+			name: "under total internal reflection",
+			r:    Ray{Point{0, 0, math.Sqrt(2) / 2}, Vector{0, 1, 0}, 0},
+			hit:  1,
+			want: 1.0,
+		},
+		{
+			name: "with a perpendicular viewing angle",
+			r:    Ray{Point{0, 0, 0}, Vector{0, 1, 0}, 0},
+			hit:  1,
+			want: 0.04,
+		},
+		{
+			name: "with small angle and n2 > n1",
+			r:    Ray{Point{0, 0.99, -2}, Vector{0, 0, 1}, 0},
+			hit:  0,
+			want: 0.48873,
+		},
+	}
+	s := glassSphere(MakeIdentity(), 1.5)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x := Intersections(s.Intersect(test.r))[test.hit]
+			got, want := x.schlick(), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
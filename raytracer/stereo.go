@@ -0,0 +1,110 @@
+package raytracer
+
+import (
+	"context"
+	"errors"
+)
+
+// Stereo describes a pair of eyes looking through a Camera, for
+// rendering views for a stereo display.
+type Stereo struct {
+	// Distance between the eyes, in world units, centered on the camera.
+	Interocular float64
+	// Distance along the view direction at which the eyes' views line up
+	// (zero parallax). Nearer things appear in front of the display,
+	// farther things behind it. Must be positive or rendering fails.
+	Convergence float64
+}
+
+// Returns cameras for the left and right eyes. Each keeps cam's view
+// direction and shifts its film to converge (an off-axis pair, which
+// unlike toeing the cameras in keeps vertical edges aligned).
+func (s Stereo) Eyes(cam *Camera) (left, right *Camera) {
+	l, r := *cam, *cam
+	// Camera space x runs right to left, so the left eye is at +x.
+	l.Projection = stereoProjection{cam.projection(), s.Interocular / 2, s.Convergence}
+	r.Projection = stereoProjection{cam.projection(), -s.Interocular / 2, s.Convergence}
+	return &l, &r
+}
+
+// Renders the views for each eye as RenderContext does, returning the
+// combined stats. If ctx is done first, rows not yet rendered in either
+// view are black; if the settings are invalid, both views are nil.
+func (cam *Camera) RenderStereo(ctx context.Context, w World, s Stereo, opts RenderOptions) (left, right *Canvas, stats RenderStats, err error) {
+	lcam, rcam := s.Eyes(cam)
+	left, stats, err = lcam.RenderContext(ctx, w, opts)
+	if left == nil {
+		return nil, nil, stats, err
+	}
+	if err != nil {
+		return left, MakeCanvas(cam.Width, cam.Height), stats, err
+	}
+	right, rstats, err := rcam.RenderContext(ctx, w, opts)
+	stats.add(rstats)
+	return left, right, stats, err
+}
+
+// stereoProjection moves the eye of another projection sideways by
+// offset, turning its rays to meet the unmoved rays at the convergence
+// distance.
+type stereoProjection struct {
+	base        Projection
+	offset      float64
+	convergence float64
+}
+
+func (p stereoProjection) Project(cam *Camera, px, py float64) (Point, Vector, bool) {
+	origin, dir, ok := p.base.Project(cam, px, py)
+	eye := Point{origin.X + p.offset, origin.Y, origin.Z}
+	if dir.Z >= 0 {
+		// Never reaches the convergence plane.
+		return eye, dir, ok
+	}
+	t := (p.convergence + origin.Z) / -dir.Z
+	return eye, origin.PlusV(dir.Scale(t)).Minus(eye), ok
+}
+
+func (p stereoProjection) validate() error {
+	if p.convergence <= 0 {
+		return errors.New("stereo: Convergence must be positive")
+	}
+	if v, ok := p.base.(projectionValidator); ok {
+		return v.validate()
+	}
+	return nil
+}
+
+// Returns a canvas with left and right placed side by side, as stereo
+// displays and viewers commonly take them.
+func SideBySide(left, right *Canvas) *Canvas {
+	checkSameSize(left, right)
+	c := MakeCanvas(2*left.Width, left.Height)
+	for y := 0; y < left.Height; y++ {
+		for x := 0; x < left.Width; x++ {
+			c.Set(x, y, left.Get(x, y))
+			c.Set(left.Width+x, y, right.Get(x, y))
+		}
+	}
+	return c
+}
+
+// Returns a red/cyan anaglyph of the two views, for viewing with the red
+// filter over the left eye: red comes from left and green and blue from
+// right.
+func Anaglyph(left, right *Canvas) *Canvas {
+	checkSameSize(left, right)
+	c := MakeCanvas(left.Width, left.Height)
+	for y := 0; y < left.Height; y++ {
+		for x := 0; x < left.Width; x++ {
+			r := right.Get(x, y)
+			c.Set(x, y, Color{left.Get(x, y).R, r.G, r.B})
+		}
+	}
+	return c
+}
+
+func checkSameSize(c1, c2 *Canvas) {
+	if c1.Width != c2.Width || c1.Height != c2.Height {
+		panic("canvas sizes differ")
+	}
+}
@@ -0,0 +1,111 @@
+package raytracer
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestStereoEyes(t *testing.T) {
+	cam := NewCamera(21, 11, math.Pi/2, MakeIdentity())
+	left, right := Stereo{Interocular: 0.2, Convergence: 5}.Eyes(cam)
+	tests := []struct {
+		name       string
+		px, py     float64
+		wantOrigin Point
+		eye        *Camera
+	}{
+		{"left center", 10.5, 5.5, Point{0.1, 0, 0}, left},
+		{"right center", 10.5, 5.5, Point{-0.1, 0, 0}, right},
+		{"left corner", 0.5, 0.5, Point{0.1, 0, 0}, left},
+		{"right corner", 0.5, 0.5, Point{-0.1, 0, 0}, right},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, _ := test.eye.sampleRay(test.px, test.py, newPixelRand(1, 0, 0))
+			if !approxEq(r.orig, test.wantOrigin) {
+				t.Errorf("origin: %s", approxError(r.orig, test.wantOrigin))
+			}
+			// Each eye's ray meets the center camera's at the convergence
+			// distance.
+			center, _ := cam.sampleRay(test.px, test.py, newPixelRand(1, 0, 0))
+			want := center.position(5 / -center.dir.Z)
+			got := r.position((-5 - r.orig.Z) / r.dir.Z)
+			if !approxEq(got, want) {
+				t.Errorf("at convergence: %s", approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestRenderStereo(t *testing.T) {
+	cam := defaultWorldCamera(20, 10)
+	// The default world's spheres are nearer than the convergence
+	// distance, so appear shifted between the views.
+	left, right, stats, err := cam.RenderStereo(context.Background(), NewDefaultWorld(), Stereo{0.5, 10}, DefaultRenderOptions())
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	if got, want := stats.Samples, 2*20*10; got != want {
+		t.Errorf("Samples = %d; want %d", got, want)
+	}
+	differ := false
+	for y := 0; y < cam.Height; y++ {
+		for x := 0; x < cam.Width; x++ {
+			if left.Get(x, y) != right.Get(x, y) {
+				differ = true
+			}
+		}
+	}
+	if !differ {
+		t.Error("left and right views are identical")
+	}
+}
+
+func TestRenderStereoInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     Stereo
+		ortho bool
+	}{
+		{name: "no convergence", s: Stereo{Interocular: 0.5}},
+		{name: "orthographic without size", s: Stereo{0.5, 10}, ortho: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cam := defaultWorldCamera(20, 10)
+			if test.ortho {
+				cam.Projection = OrthographicProjection{}
+			}
+			left, right, _, err := cam.RenderStereo(context.Background(), NewDefaultWorld(), test.s, DefaultRenderOptions())
+			if left != nil || right != nil || err == nil {
+				t.Errorf("got views %v, %v and error %v; want nil views and an error", left, right, err)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	left, right := MakeCanvas(2, 1), MakeCanvas(2, 1)
+	left.Set(1, 0, Red())
+	right.Set(0, 0, White())
+	c := SideBySide(left, right)
+	if c.Width != 4 || c.Height != 1 {
+		t.Fatalf("size %dx%d; want 4x1", c.Width, c.Height)
+	}
+	for x, want := range []Color{Black(), Red(), White(), Black()} {
+		if got := c.Get(x, 0); got != want {
+			t.Errorf("pixel %d = %v; want %v", x, got, want)
+		}
+	}
+}
+
+func TestAnaglyph(t *testing.T) {
+	left, right := MakeCanvas(1, 1), MakeCanvas(1, 1)
+	left.Set(0, 0, Color{0.2, 0.4, 0.6})
+	right.Set(0, 0, Color{0.7, 0.8, 0.9})
+	got, want := Anaglyph(left, right).Get(0, 0), Color{0.2, 0.8, 0.9}
+	if got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
@@ -12,6 +12,7 @@ func approxOpts() []cmp.Option {
 		cmpopts.EquateApprox(0, 0.0001),
 		cmp.AllowUnexported(Point{}, Vector{}, Color{}, Matrix{}, Ray{}),
 		IntersectionTComparer(),
+		MaterialIntersectionTComparer(),
 	}
 }
 
@@ -0,0 +1,117 @@
+package raytracer
+
+import "math"
+
+// A flat triangle with vertices given in the space of its parent; it
+// has no transform of its own unless animated.
+type triangle struct {
+	shapeBase
+	p1, p2, p3 Point
+	// Edges from p1 to p2 and from p1 to p3.
+	e1, e2 Vector
+	normal Vector
+}
+
+func NewTriangle(p1, p2, p3 Point) Shape {
+	return newTriangle(p1, p2, p3)
+}
+
+func newTriangle(p1, p2, p3 Point) *triangle {
+	e1 := p2.Minus(p1)
+	e2 := p3.Minus(p1)
+	normal := e2.Cross(e1).Norm()
+	base := shapeBase{MakeIdentity(), MakeIdentity(), MakeIdentity(), NewMaterial(), nil, true, nil}
+	return &triangle{base, p1, p2, p3, e1, e2, normal}
+}
+
+func (tr *triangle) Intersect(r Ray) []MaterialIntersection {
+	t, u, v, ok := tr.intersectUV(tr.objectRay(r))
+	if !ok {
+		return []MaterialIntersection{}
+	}
+	normalV := tr.normalToWorld(tr.normal, r.time)
+	return []MaterialIntersection{NewMaterialIntersectionWithUV(r, t, u, v, normalV, tr.material)}
+}
+
+// Returns r, given in the space of the triangle's parent, in the
+// triangle's own space, which differs only if it is animated.
+func (tr *triangle) objectRay(r Ray) Ray {
+	if tr.identity {
+		return r
+	}
+	return r.xform(tr.ixfAt(r.time))
+}
+
+// Intersects r with the triangle using the Möller–Trumbore algorithm,
+// returning t and the barycentric coordinates u, v of the hit.
+func (tr *triangle) intersectUV(r Ray) (t, u, v float64, ok bool) {
+	dirCrossE2 := r.dir.Cross(tr.e2)
+	det := tr.e1.Dot(dirCrossE2)
+	if math.Abs(det) < epsilon {
+		// Ray is parallel to the triangle.
+		return 0, 0, 0, false
+	}
+	f := 1.0 / det
+	p1ToOrigin := r.orig.Minus(tr.p1)
+	u = f * p1ToOrigin.Dot(dirCrossE2)
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	originCrossE1 := p1ToOrigin.Cross(tr.e1)
+	v = f * r.dir.Dot(originCrossE1)
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	t = f * tr.e2.Dot(originCrossE1)
+	return t, u, v, true
+}
+
+func (tr *triangle) Bounds() Bounds {
+	return tr.parentBounds(EmptyBounds().add(tr.p1).add(tr.p2).add(tr.p3))
+}
+
+func (tr *triangle) NormalAt(p Point) Vector {
+	return tr.normalToWorld(tr.normal, 0)
+}
+
+// Returns the barycentric coordinates u, v of p, which must lie in the
+// triangle's plane, such that p = p1 + u*e1 + v*e2.
+func (tr *triangle) barycentric(p Point) (u, v float64) {
+	p1ToP := p.Minus(tr.p1)
+	d11 := tr.e1.Dot(tr.e1)
+	d12 := tr.e1.Dot(tr.e2)
+	d22 := tr.e2.Dot(tr.e2)
+	dp1 := p1ToP.Dot(tr.e1)
+	dp2 := p1ToP.Dot(tr.e2)
+	denom := d11*d22 - d12*d12
+	u = (d22*dp1 - d12*dp2) / denom
+	v = (d11*dp2 - d12*dp1) / denom
+	return u, v
+}
+
+// A triangle whose normal is interpolated from normals at its vertices.
+type smoothTriangle struct {
+	*triangle
+	n1, n2, n3 Vector
+}
+
+func NewSmoothTriangle(p1, p2, p3 Point, n1, n2, n3 Vector) Shape {
+	return &smoothTriangle{newTriangle(p1, p2, p3), n1, n2, n3}
+}
+
+func (st *smoothTriangle) Intersect(r Ray) []MaterialIntersection {
+	t, u, v, ok := st.intersectUV(st.objectRay(r))
+	if !ok {
+		return []MaterialIntersection{}
+	}
+	normalV := st.normalToWorld(st.normalAtUV(u, v), r.time)
+	return []MaterialIntersection{NewMaterialIntersectionWithUV(r, t, u, v, normalV, st.material)}
+}
+
+func (st *smoothTriangle) NormalAt(p Point) Vector {
+	return st.normalToWorld(st.normalAtUV(st.barycentric(st.worldToObject(p, 0))), 0)
+}
+
+func (st *smoothTriangle) normalAtUV(u, v float64) Vector {
+	return st.n2.Scale(u).Plus(st.n3.Scale(v)).Plus(st.n1.Scale(1 - u - v)).Norm()
+}
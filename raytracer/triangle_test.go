@@ -0,0 +1,96 @@
+package raytracer
+
+import "testing"
+
+func TestNewTriangle(t *testing.T) {
+	tr := newTriangle(Point{0, 1, 0}, Point{-1, 0, 0}, Point{1, 0, 0})
+	tests := []struct {
+		name string
+		got  Vector
+		want Vector
+	}{
+		{"e1", tr.e1, Vector{-1, -1, 0}},
+		{"e2", tr.e2, Vector{1, -1, 0}},
+		{"normal", tr.normal, Vector{0, 0, -1}},
+		{"NormalAt", tr.NormalAt(Point{0, 0.5, 0}), Vector{0, 0, -1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !approxEq(test.got, test.want) {
+				t.Error(approxError(test.got, test.want))
+			}
+		})
+	}
+}
+
+func TestTriangleIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ray
+		want []MaterialIntersection
+	}{
+		{
+			name: "parallel ray",
+			r:    Ray{Point{0, -1, -2}, Vector{0, 1, 0}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses p1-p3 edge",
+			r:    Ray{Point{1, 1, -2}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses p1-p2 edge",
+			r:    Ray{Point{-1, 1, -2}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "misses p2-p3 edge",
+			r:    Ray{Point{0, -1, -2}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{},
+		},
+		{
+			name: "strikes triangle",
+			r:    Ray{Point{0, 0.5, -2}, Vector{0, 0, 1}, 0},
+			want: []MaterialIntersection{{t: 2}},
+		},
+	}
+	tr := NewTriangle(Point{0, 1, 0}, Point{-1, 0, 0}, Point{1, 0, 0})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := tr.Intersect(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func newTestSmoothTriangle() Shape {
+	return NewSmoothTriangle(
+		Point{0, 1, 0}, Point{-1, 0, 0}, Point{1, 0, 0},
+		Vector{0, 1, 0}, Vector{-1, 0, 0}, Vector{1, 0, 0})
+}
+
+func TestSmoothTriangleIntersection(t *testing.T) {
+	r := Ray{Point{-0.2, 0.3, -2}, Vector{0, 0, 1}, 0}
+	xs := newTestSmoothTriangle().Intersect(r)
+	if len(xs) != 1 {
+		t.Fatalf("got %d intersections; want 1", len(xs))
+	}
+	got, want := [2]float64{xs[0].u, xs[0].v}, [2]float64{0.45, 0.25}
+	if !approxEq(got, want) {
+		t.Errorf("u, v: %s", approxError(got, want))
+	}
+	gotN, wantN := xs[0].normalV, Vector{-0.5547, 0.83205, 0}
+	if !approxEq(gotN, wantN) {
+		t.Errorf("normal: %s", approxError(gotN, wantN))
+	}
+}
+
+func TestSmoothTriangleNormalAt(t *testing.T) {
+	got, want := newTestSmoothTriangle().NormalAt(Point{-0.2, 0.3, 0}), Vector{-0.5547, 0.83205, 0}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
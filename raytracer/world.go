@@ -3,22 +3,36 @@ package raytracer
 import "sort"
 
 type World interface {
-	Intersect(r Ray) []Intersection
+	// Returns all intersections of r with the world's shapes, sorted by t.
+	Intersect(r Ray) []MaterialIntersection
+	// Returns the color seen along r, lit by all of the world's lights.
+	ColorAt(r Ray) Color
+	// Returns whether any shape lies between point and light at the
+	// given time. Area lights are treated as points at their middle.
+	IsShadowed(point Point, light Light, time float64) bool
+	// Returns the fraction of light seen from point at the given time,
+	// 0 or 1 unless light is an AreaLight.
+	LightVisibility(point Point, light Light, time float64) float64
 
 	AddShape(s Shape)
 	AddLight(l Light)
 
 	Shapes() []Shape
 	Lights() []Light
+
+	// Returns what rays that miss every shape see; black by default.
+	Background() Background
+	SetBackground(b Background)
 }
 
 type world struct {
-	shapes []Shape
-	lights []Light
+	shapes     []Shape
+	lights     []Light
+	background Background
 }
 
 func (w *world) Intersect(r Ray) []MaterialIntersection {
-	xs := []Intersection{}
+	xs := []MaterialIntersection{}
 	for _, s := range w.shapes {
 		xs = append(xs, s.Intersect(r)...)
 	}
@@ -28,6 +42,41 @@ func (w *world) Intersect(r Ray) []MaterialIntersection {
 	return xs
 }
 
+func (w *world) ColorAt(r Ray) Color {
+	return NewLightShader().ColorAt(w, r, DefaultMaxDepth, &RenderStats{})
+}
+
+func (w *world) IsShadowed(point Point, light Light, time float64) bool {
+	v, distance, _ := light.Illuminate(point)
+	return occluded(w, Ray{point, v, time}, distance)
+}
+
+func (w *world) LightVisibility(point Point, light Light, time float64) float64 {
+	area, ok := light.(AreaLight)
+	if !ok {
+		if w.IsShadowed(point, light, time) {
+			return 0
+		}
+		return 1
+	}
+	points := area.SamplePoints(point)
+	visible := 0
+	for _, lp := range points {
+		v := lp.Minus(point)
+		distance := v.Magnitude()
+		if !occluded(w, Ray{point, v.Scale(1 / distance), time}, distance) {
+			visible++
+		}
+	}
+	return float64(visible) / float64(len(points))
+}
+
+// Returns whether r hits anything in w nearer than distance.
+func occluded(w World, r Ray, distance float64) bool {
+	x := hit(Intersections(w.Intersect(r)))
+	return x != nil && x.t < distance
+}
+
 func (w *world) AddShape(s Shape) {
 	w.shapes = append(w.shapes, s)
 }
@@ -44,8 +93,16 @@ func (w *world) Lights() []Light {
 	return w.lights
 }
 
+func (w *world) Background() Background {
+	return w.background
+}
+
+func (w *world) SetBackground(b Background) {
+	w.background = b
+}
+
 func NewEmptyWorld() World {
-	return &world{[]Shape{}, []Light{}}
+	return &world{[]Shape{}, []Light{}, SolidBackground{Black()}}
 }
 
 func NewDefaultWorld() World {
@@ -4,15 +4,129 @@ import "testing"
 
 func TestWorldIntersect(t *testing.T) {
 	w := NewDefaultWorld()
-	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}}
+	r := Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0}
 	got := w.Intersect(r)
-	want := []Intersection{
-		NewIntersection(4, nil),
-		NewIntersection(4.5, nil),
-		NewIntersection(5.5, nil),
-		NewIntersection(6, nil),
+	want := []MaterialIntersection{
+		MaterialIntersection{t: 4},
+		MaterialIntersection{t: 4.5},
+		MaterialIntersection{t: 5.5},
+		MaterialIntersection{t: 6},
 	}
 	if !approxEq(got, want) {
 		t.Errorf(approxError(got, want))
 	}
 }
+
+func TestWorldColorAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(w World)
+		r     Ray
+		want  Color
+	}{
+		{
+			name:  "ray misses",
+			setup: func(w World) {},
+			r:     Ray{Point{0, 0, -5}, Vector{0, 1, 0}, 0},
+			want:  Black(),
+		},
+		{
+			name:  "ray hits",
+			setup: func(w World) {},
+			r:     Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want:  Color{0.38066, 0.47583, 0.2855},
+		},
+		{
+			name: "intersection behind ray",
+			setup: func(w World) {
+				w.Shapes()[0].Material().Ambient = 1
+				w.Shapes()[1].Material().Ambient = 1
+			},
+			r:    Ray{Point{0, 0, 0.75}, Vector{0, 0, -1}, 0},
+			want: White(),
+		},
+		{
+			name: "lit by every light",
+			setup: func(w World) {
+				w.AddLight(NewPointLight(Point{-10, 10, -10}, White()))
+			},
+			r:    Ray{Point{0, 0, -5}, Vector{0, 0, 1}, 0},
+			want: Color{0.38066, 0.47583, 0.2855}.scale(2),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewDefaultWorld()
+			test.setup(w)
+			got, want := w.ColorAt(test.r), test.want
+			if !approxEq(got, want) {
+				t.Error(approxError(got, want))
+			}
+		})
+	}
+}
+
+func TestWorldIsShadowed(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Point
+		want bool
+	}{
+		{
+			name: "nothing collinear with point and light",
+			p:    Point{0, 10, 0},
+			want: false,
+		},
+		{
+			name: "object between point and light",
+			p:    Point{10, -10, 10},
+			want: true,
+		},
+		{
+			name: "object behind light",
+			p:    Point{-20, 20, -20},
+			want: false,
+		},
+		{
+			name: "object behind point",
+			p:    Point{-2, 2, -2},
+			want: false,
+		},
+	}
+	w := NewDefaultWorld()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, want := w.IsShadowed(test.p, w.Lights()[0], 0), test.want
+			if got != want {
+				t.Errorf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWorldColorAtInShadow(t *testing.T) {
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{0, 0, -10}, White()))
+	w.AddShape(NewSphere(MakeIdentity()))
+	w.AddShape(NewSphere(MakeTranslation(0, 0, 10)))
+	r := Ray{Point{0, 0, 5}, Vector{0, 0, 1}, 0}
+	got, want := w.ColorAt(r), Color{0.1, 0.1, 0.1}
+	if !approxEq(got, want) {
+		t.Error(approxError(got, want))
+	}
+}
+
+func TestWorldIsShadowedByMovingShape(t *testing.T) {
+	w := NewEmptyWorld()
+	w.AddLight(NewPointLight(Point{2, 10, 0}, White()))
+	w.AddShape(movingSphere())
+	// The sphere passes between the point and the light halfway through
+	// its motion.
+	p := Point{2, -10, 0}
+	if w.IsShadowed(p, w.Lights()[0], 0) {
+		t.Error("shadowed at time 0; want not")
+	}
+	if !w.IsShadowed(p, w.Lights()[0], 0.5) {
+		t.Error("not shadowed at time 0.5; want shadowed")
+	}
+}